@@ -0,0 +1,322 @@
+package main
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// azureDevOpsAPIVersion is the REST API version requested from Azure DevOps
+const azureDevOpsAPIVersion = "7.1"
+
+// maxAzureDevOpsPageSize bounds the $top value of pull request queries
+const maxAzureDevOpsPageSize = 1000
+
+// Azure DevOps reviewer votes
+const (
+	azureVoteApproved            = 10
+	azureVoteApprovedSuggestions = 5
+	azureVoteWaitingForAuthor    = -5
+	azureVoteRejected            = -10
+)
+
+// AzureDevOpsClient fetches completed pull requests from the Azure DevOps (Azure Repos) REST API
+type AzureDevOpsClient struct {
+	httpClient *http.Client
+	token      string
+}
+
+// azureIdentity is a user reference in Azure DevOps API responses
+type azureIdentity struct {
+	DisplayName string `json:"displayName"`
+	UniqueName  string `json:"uniqueName"`
+}
+
+// azurePullRequest is the subset of an Azure DevOps pull request used by the report
+type azurePullRequest struct {
+	PullRequestID int            `json:"pullRequestId"`
+	Title         string         `json:"title"`
+	Description   string         `json:"description"`
+	Labels        []Label        `json:"labels"`
+	Status        string         `json:"status"`
+	CreationDate  time.Time      `json:"creationDate"`
+	ClosedDate    *time.Time     `json:"closedDate"`
+	CreatedBy     azureIdentity  `json:"createdBy"`
+	ClosedBy      *azureIdentity `json:"closedBy"`
+	TargetRefName string         `json:"targetRefName"`
+	Reviewers     []struct {
+		azureIdentity
+		Vote        int  `json:"vote"`
+		IsContainer bool `json:"isContainer"` // Group reviewers mirror their members' votes
+	} `json:"reviewers"`
+}
+
+// NewAzureDevOpsClient creates an Azure DevOps client authenticated with the AZURE_DEVOPS_PAT personal access token
+func NewAzureDevOpsClient() *AzureDevOpsClient {
+	return &AzureDevOpsClient{
+		httpClient: &http.Client{Timeout: 60 * time.Second},
+		token:      os.Getenv("AZURE_DEVOPS_PAT"),
+	}
+}
+
+// FetchPullRequests fetches the pull requests of a repository in the requested states; the owner is "organization/project"
+func (ac *AzureDevOpsClient) FetchPullRequests(repository Repository, filter *PRFilter, workerConfig *WorkerConfig) ([]PullRequest, error) {
+	pageSize := maxAzureDevOpsPageSize
+	if workerConfig != nil && workerConfig.PageSize > 0 && workerConfig.PageSize < pageSize {
+		pageSize = workerConfig.PageSize
+	}
+
+	query := url.Values{}
+	query.Set("searchCriteria.status", azureQueryStatus(filter))
+	query.Set("api-version", azureDevOpsAPIVersion)
+	query.Set("$top", strconv.Itoa(pageSize))
+	// Active pull requests have no closed date, so they are filtered by creation date afterwards
+	if filter != nil && (filter.StartDate != nil || filter.EndDate != nil) && !wantsState(filter, stateOpen) {
+		query.Set("searchCriteria.queryTimeRangeType", "closed")
+		if filter.StartDate != nil {
+			query.Set("searchCriteria.minTime", filter.StartDate.Format(time.RFC3339))
+		}
+		if filter.EndDate != nil {
+			query.Set("searchCriteria.maxTime", filter.EndDate.Format(time.RFC3339))
+		}
+	}
+
+	base := fmt.Sprintf("%s/%s/_apis/git/repositories/%s/pullrequests", repository.BaseURL, azurePath(repository.Owner), url.PathEscape(repository.Name))
+
+	var pullRequests []azurePullRequest
+	for skip := 0; ; skip += pageSize {
+		query.Set("$skip", strconv.Itoa(skip))
+		var page struct {
+			Value []azurePullRequest `json:"value"`
+		}
+		if _, err := ac.get(base+"?"+query.Encode(), &page); err != nil {
+			return nil, fmt.Errorf("failed to fetch pull requests for %s/%s: %w", repository.Owner, repository.Name, err)
+		}
+		pullRequests = append(pullRequests, page.Value...)
+
+		if filter != nil && filter.Limit > 0 && len(pullRequests) >= filter.Limit {
+			pullRequests = pullRequests[:filter.Limit]
+			break
+		}
+		if len(page.Value) < pageSize {
+			break
+		}
+	}
+
+	var prs []PullRequest
+	for _, ap := range pullRequests {
+		pr := PullRequest{
+			Number:      ap.PullRequestID,
+			Title:       ap.Title,
+			State:       azureState(ap.Status),
+			ClosedAt:    ap.ClosedDate,
+			CreatedAt:   ap.CreationDate,
+			BaseRefName: strings.TrimPrefix(ap.TargetRefName, "refs/heads/"),
+			Body:        ap.Description,
+			Labels:      ap.Labels,
+			URL: fmt.Sprintf("%s/%s/_git/%s/pullrequest/%d", repository.BaseURL, azurePath(repository.Owner),
+				url.PathEscape(repository.Name), ap.PullRequestID),
+		}
+		if pr.State == stateMerged {
+			pr.MergedAt = ap.ClosedDate
+		}
+		pr.Author.Login = ap.CreatedBy.UniqueName
+		if ap.ClosedBy != nil && pr.State == stateMerged {
+			pr.MergedBy = &Actor{Login: ap.ClosedBy.UniqueName}
+		}
+
+		for _, reviewer := range ap.Reviewers {
+			if reviewer.IsContainer {
+				continue
+			}
+			// Votes are not timestamped; the vote recorded on a completed PR is the one it was merged with
+			review := Review{Author: Actor{Login: reviewer.UniqueName}}
+			if ap.ClosedDate != nil {
+				review.SubmittedAt = *ap.ClosedDate
+			}
+			switch reviewer.Vote {
+			case azureVoteApproved, azureVoteApprovedSuggestions:
+				review.State = "APPROVED"
+			case azureVoteWaitingForAuthor, azureVoteRejected:
+				review.State = "CHANGES_REQUESTED"
+			default:
+				continue
+			}
+			pr.Reviews = append(pr.Reviews, review)
+		}
+		prs = append(prs, pr)
+	}
+	prs = filterByPeriod(prs, filter)
+
+	// Changed files and commits are only fetched for pull requests that made it through the date
+	// filter; Azure DevOps does not count changed lines, so sizes are in files only. A pull request
+	// whose changes or commits cannot be fetched is reported as unverified.
+	for i := range prs {
+		ref := fmt.Sprintf("%s/%s#%d", repository.Owner, repository.Name, prs[i].Number)
+		prURL := fmt.Sprintf("%s/%d", base, prs[i].Number)
+		files, err := ac.fetchChangedFiles(prURL)
+		if err != nil {
+			markUnverified(&prs[i], ref, partFiles, err)
+		}
+		prs[i].Files = files
+		prs[i].ChangedFiles = len(files)
+		prs[i].LinesUnknown = true
+
+		commits, err := ac.fetchCommits(prURL)
+		if err != nil {
+			markUnverified(&prs[i], ref, partCommits, err)
+		}
+		prs[i].Commits = commits
+	}
+
+	return prs, nil
+}
+
+// fetchChangedFiles returns the files changed by the last iteration of a pull request, which
+// covers every change of the pull request against its target branch
+func (ac *AzureDevOpsClient) fetchChangedFiles(prURL string) ([]ChangedFile, error) {
+	var iterations struct {
+		Value []struct {
+			ID int `json:"id"`
+		} `json:"value"`
+	}
+	if _, err := ac.get(prURL+"/iterations?api-version="+azureDevOpsAPIVersion, &iterations); err != nil {
+		return nil, err
+	}
+	if len(iterations.Value) == 0 {
+		return nil, nil
+	}
+	last := iterations.Value[len(iterations.Value)-1].ID
+
+	var files []ChangedFile
+	for skip := 0; ; {
+		var page struct {
+			ChangeEntries []struct {
+				Item struct {
+					Path     string `json:"path"`
+					IsFolder bool   `json:"isFolder"`
+				} `json:"item"`
+			} `json:"changeEntries"`
+			NextSkip int `json:"nextSkip"`
+		}
+		rawURL := fmt.Sprintf("%s/iterations/%d/changes?api-version=%s&$top=%d&$skip=%d", prURL, last, azureDevOpsAPIVersion, maxAzureDevOpsPageSize, skip)
+		if _, err := ac.get(rawURL, &page); err != nil {
+			return nil, err
+		}
+		for _, entry := range page.ChangeEntries {
+			if !entry.Item.IsFolder && entry.Item.Path != "" {
+				files = append(files, ChangedFile{Path: strings.TrimPrefix(entry.Item.Path, "/")})
+			}
+		}
+		if page.NextSkip == 0 || len(page.ChangeEntries) == 0 {
+			return files, nil
+		}
+		skip = page.NextSkip
+	}
+}
+
+// azureCommit is a commit of a pull request
+type azureCommit struct {
+	CommitID string `json:"commitId"`
+	Comment  string `json:"comment"`
+	Author   struct {
+		Date time.Time `json:"date"`
+	} `json:"author"`
+	Committer struct {
+		Date time.Time `json:"date"`
+	} `json:"committer"`
+}
+
+// fetchCommits returns the commits of a pull request, oldest first like gh, following the
+// continuation token Azure DevOps returns while more commits remain
+func (ac *AzureDevOpsClient) fetchCommits(prURL string) ([]PRCommit, error) {
+	var listed []azureCommit
+	for token := ""; ; {
+		rawURL := fmt.Sprintf("%s/commits?api-version=%s&$top=%d", prURL, azureDevOpsAPIVersion, maxAzureDevOpsPageSize)
+		if token != "" {
+			rawURL += "&continuationToken=" + url.QueryEscape(token)
+		}
+		var page struct {
+			Value []azureCommit `json:"value"`
+		}
+		header, err := ac.get(rawURL, &page)
+		if err != nil {
+			return nil, err
+		}
+		listed = append(listed, page.Value...)
+
+		token = header.Get("x-ms-continuationtoken")
+		if token == "" || len(page.Value) == 0 {
+			break
+		}
+	}
+
+	// Azure DevOps lists pull request commits newest first
+	var commits []PRCommit
+	for i := len(listed) - 1; i >= 0; i-- {
+		commit := listed[i]
+		headline, body, _ := strings.Cut(strings.TrimSpace(commit.Comment), "\n")
+		commits = append(commits, PRCommit{
+			Oid:             commit.CommitID,
+			MessageHeadline: headline,
+			MessageBody:     strings.TrimSpace(body),
+			AuthoredDate:    commit.Author.Date,
+			CommittedDate:   commit.Committer.Date,
+		})
+	}
+	return commits, nil
+}
+
+// get performs an authenticated GET against the Azure DevOps REST API and decodes the JSON response
+func (ac *AzureDevOpsClient) get(rawURL string, result interface{}) (http.Header, error) {
+	header := http.Header{}
+	if ac.token != "" {
+		// Personal access tokens are sent as the password of basic authentication
+		header.Set("Authorization", "Basic "+base64.StdEncoding.EncodeToString([]byte(":"+ac.token)))
+	}
+	return getJSON(ac.httpClient, rawURL, header, result)
+}
+
+// azurePath escapes each segment of an "organization/project" owner
+func azurePath(owner string) string {
+	segments := strings.Split(owner, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	return strings.Join(segments, "/")
+}
+
+// azureQueryStatus returns the pull request status criterion covering the requested states
+func azureQueryStatus(filter *PRFilter) string {
+	states := queryStates(filter)
+	if len(states) > 1 {
+		return "all"
+	}
+	switch states[0] {
+	case stateClosed:
+		return "abandoned"
+	case stateOpen:
+		return "active"
+	default:
+		return "completed"
+	}
+}
+
+// azureState maps Azure DevOps pull request statuses to the GitHub states used in reports
+func azureState(status string) string {
+	switch status {
+	case "completed":
+		return "MERGED"
+	case "abandoned":
+		return "CLOSED"
+	case "active":
+		return "OPEN"
+	default:
+		return strings.ToUpper(status)
+	}
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const azureTestPullRequests = "/acme/platform/_apis/git/repositories/api/pullrequests"
+
+// azureTestResponses returns a repository with one completed pull request whose commits span two pages
+func azureTestResponses() map[string]fakeResponse {
+	return map[string]fakeResponse{
+		azureTestPullRequests + "?%24skip=0&%24top=1000&api-version=7.1&searchCriteria.status=completed": {
+			Body: `{"value": [{"pullRequestId": 5, "title": "Add billing", "status": "completed",
+				"creationDate": "2024-03-01T10:00:00Z", "closedDate": "2024-03-02T10:00:00Z",
+				"createdBy": {"uniqueName": "alice@acme.com"}, "closedBy": {"uniqueName": "bob@acme.com"},
+				"targetRefName": "refs/heads/main", "reviewers": [
+					{"uniqueName": "carol@acme.com", "vote": 10},
+					{"uniqueName": "dave@acme.com", "vote": -5},
+					{"uniqueName": "[acme]\\Reviewers", "vote": 10, "isContainer": true}]}]}`,
+		},
+		azureTestPullRequests + "/5/iterations?api-version=7.1": {
+			Body: `{"value": [{"id": 1}, {"id": 2}]}`,
+		},
+		azureTestPullRequests + "/5/iterations/2/changes?api-version=7.1&$top=1000&$skip=0": {
+			Body: `{"changeEntries": [{"item": {"path": "/src", "isFolder": true}}, {"item": {"path": "/src/app.go"}}], "nextSkip": 2}`,
+		},
+		azureTestPullRequests + "/5/iterations/2/changes?api-version=7.1&$top=1000&$skip=2": {
+			Body: `{"changeEntries": [{"item": {"path": "/README.md"}}]}`,
+		},
+		azureTestPullRequests + "/5/commits?api-version=7.1&$top=1000": {
+			Header: map[string]string{"x-ms-continuationtoken": "next page"},
+			Body:   `{"value": [{"commitId": "ccc", "comment": "Third\n\nDetails"}, {"commitId": "bbb", "comment": "Second"}]}`,
+		},
+		azureTestPullRequests + "/5/commits?api-version=7.1&$top=1000&continuationToken=next+page": {
+			Body: `{"value": [{"commitId": "aaa", "comment": "First"}]}`,
+		},
+	}
+}
+
+func TestAzureDevOpsFetchPullRequests(t *testing.T) {
+	server := newFakeAPI(t, azureTestResponses())
+	client := &AzureDevOpsClient{httpClient: server.Client()}
+
+	prs, err := client.FetchPullRequests(Repository{Owner: "acme/platform", Name: "api", BaseURL: server.URL}, nil, nil)
+	if err != nil {
+		t.Fatalf("FetchPullRequests: %v", err)
+	}
+	if len(prs) != 1 {
+		t.Fatalf("got %d pull requests, want 1", len(prs))
+	}
+
+	pr := prs[0]
+	if pr.State != stateMerged || pr.Author.Login != "alice@acme.com" || mergedByLogin(pr) != "bob@acme.com" || pr.BaseRefName != "main" {
+		t.Errorf("mapped state %q, author %q, merged by %q, base %q", pr.State, pr.Author.Login, mergedByLogin(pr), pr.BaseRefName)
+	}
+	if got := approvers(pr); !reflect.DeepEqual(got, []string{"carol@acme.com"}) {
+		t.Errorf("approvers = %v, want [carol@acme.com] without group reviewers", got)
+	}
+	if got := filePaths(pr.Files); !reflect.DeepEqual(got, []string{"src/app.go", "README.md"}) {
+		t.Errorf("files = %v, want the files of both change pages", got)
+	}
+	if !pr.LinesUnknown || pr.FilesIncomplete {
+		t.Errorf("lines unknown %v, files incomplete %v, want unknown lines and complete files", pr.LinesUnknown, pr.FilesIncomplete)
+	}
+	if got := commitOids(pr.Commits); !reflect.DeepEqual(got, []string{"aaa", "bbb", "ccc"}) {
+		t.Errorf("commits = %v, want both continuation pages oldest first", got)
+	}
+}
+
+func TestAzureDevOpsFetchPullRequestsMarksFailedDetailsUnverified(t *testing.T) {
+	tests := []struct {
+		name string
+		path string
+		part string
+	}{
+		{"iterations", "/5/iterations?api-version=7.1", partFiles},
+		{"commits", "/5/commits?api-version=7.1&$top=1000&continuationToken=next+page", partCommits},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			responses := azureTestResponses()
+			responses[azureTestPullRequests+tt.path] = fakeResponse{Status: http.StatusInternalServerError}
+			server := newFakeAPI(t, responses)
+			client := &AzureDevOpsClient{httpClient: server.Client()}
+
+			prs, err := client.FetchPullRequests(Repository{Owner: "acme/platform", Name: "api", BaseURL: server.URL}, nil, nil)
+			if err != nil {
+				t.Fatalf("FetchPullRequests failed for the whole repository: %v", err)
+			}
+			if len(prs) != 1 || len(prs[0].Unverified) != 1 {
+				t.Fatalf("got %d pull requests, want 1 with one unverified part", len(prs))
+			}
+			if part := prs[0].Unverified[0]; !strings.HasPrefix(part, tt.part) {
+				t.Errorf("unverified = %q, want the %s", part, tt.part)
+			}
+		})
+	}
+}
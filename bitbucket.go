@@ -0,0 +1,376 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxBitbucketPageSize is the largest page size Bitbucket Server returns by default
+const maxBitbucketPageSize = 1000
+
+// maxBitbucketChanges is Bitbucket Server's default page.max.changes, past which it stops listing
+// the changes of a pull request
+const maxBitbucketChanges = 1000
+
+// BitbucketClient fetches merged pull requests from the Bitbucket Server (Data Center) REST API
+type BitbucketClient struct {
+	httpClient *http.Client
+	token      string
+}
+
+// bitbucketUser is a user reference in Bitbucket Server API responses
+type bitbucketUser struct {
+	Name string `json:"name"`
+	Slug string `json:"slug"`
+}
+
+// bitbucketPullRequest is the subset of a Bitbucket Server pull request used by the report
+type bitbucketPullRequest struct {
+	ID          int    `json:"id"`
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	State       string `json:"state"`
+	CreatedDate int64  `json:"createdDate"` // Milliseconds since the epoch
+	UpdatedDate int64  `json:"updatedDate"`
+	ClosedDate  int64  `json:"closedDate"`
+	Author      struct {
+		User bitbucketUser `json:"user"`
+	} `json:"author"`
+	ToRef struct {
+		DisplayID string `json:"displayId"`
+	} `json:"toRef"`
+	Links struct {
+		Self []struct {
+			Href string `json:"href"`
+		} `json:"self"`
+	} `json:"links"`
+}
+
+// bitbucketActivity is a pull request activity such as an approval or the merge
+type bitbucketActivity struct {
+	Action      string        `json:"action"`
+	CreatedDate int64         `json:"createdDate"`
+	User        bitbucketUser `json:"user"`
+}
+
+// bitbucketChange is a file changed by a pull request
+type bitbucketChange struct {
+	Path struct {
+		ToString string `json:"toString"`
+	} `json:"path"`
+}
+
+// bitbucketDiff is the response of the pull request diff endpoint
+type bitbucketDiff struct {
+	Diffs []struct {
+		Source *struct {
+			ToString string `json:"toString"`
+		} `json:"source"`
+		Destination *struct {
+			ToString string `json:"toString"`
+		} `json:"destination"`
+		Hunks []struct {
+			Segments []struct {
+				Type  string            `json:"type"` // ADDED, REMOVED or CONTEXT
+				Lines []json.RawMessage `json:"lines"`
+			} `json:"segments"`
+		} `json:"hunks"`
+		Truncated bool `json:"truncated"`
+	} `json:"diffs"`
+	Truncated bool `json:"truncated"`
+}
+
+// bitbucketCommit is a commit of a pull request
+type bitbucketCommit struct {
+	ID                 string `json:"id"`
+	Message            string `json:"message"`
+	AuthorTimestamp    int64  `json:"authorTimestamp"`
+	CommitterTimestamp int64  `json:"committerTimestamp"`
+}
+
+// bitbucketPage is a page of a Bitbucket Server paged API response
+type bitbucketPage[T any] struct {
+	Values        []T  `json:"values"`
+	IsLastPage    bool `json:"isLastPage"`
+	NextPageStart int  `json:"nextPageStart"`
+}
+
+// NewBitbucketClient creates a Bitbucket Server client authenticated with BITBUCKET_TOKEN when it is set
+func NewBitbucketClient() *BitbucketClient {
+	return &BitbucketClient{
+		httpClient: &http.Client{Timeout: 60 * time.Second},
+		token:      os.Getenv("BITBUCKET_TOKEN"),
+	}
+}
+
+// FetchPullRequests fetches the pull requests of a repository in the requested states; the owner is the Bitbucket project key
+func (bc *BitbucketClient) FetchPullRequests(repository Repository, filter *PRFilter, workerConfig *WorkerConfig) ([]PullRequest, error) {
+	if repository.BaseURL == "" {
+		return nil, fmt.Errorf("base_url is required for Bitbucket Server repository %s/%s", repository.Owner, repository.Name)
+	}
+	repoPath := fmt.Sprintf("/projects/%s/repos/%s", url.PathEscape(repository.Owner), url.PathEscape(repository.Name))
+
+	pageSize := maxBitbucketPageSize
+	if workerConfig != nil && workerConfig.PageSize > 0 && workerConfig.PageSize < pageSize {
+		pageSize = workerConfig.PageSize
+	}
+
+	var pullRequests []bitbucketPullRequest
+	for start, done := 0, false; !done; {
+		query := url.Values{}
+		query.Set("state", bitbucketQueryState(filter))
+		query.Set("order", "NEWEST")
+		query.Set("limit", strconv.Itoa(pageSize))
+		query.Set("start", strconv.Itoa(start))
+		if role, login, ok := singleScopedLogin(filter); ok && role == roleAuthored {
+			query.Set("role.1", "AUTHOR")
+			query.Set("username.1", login)
+		}
+
+		var page bitbucketPage[bitbucketPullRequest]
+		if err := bc.get(repository.BaseURL, repoPath+"/pull-requests?"+query.Encode(), &page); err != nil {
+			return nil, fmt.Errorf("failed to fetch pull requests for %s/%s: %w", repository.Owner, repository.Name, err)
+		}
+
+		for _, pr := range page.Values {
+			// Pull requests are ordered by last update, which is never before the merge or decline;
+			// open pull requests may not have been updated in the period at all
+			if filter != nil && filter.StartDate != nil && !wantsState(filter, stateOpen) && bitbucketTime(pr.UpdatedDate).Before(*filter.StartDate) {
+				done = true
+				break
+			}
+			pullRequests = append(pullRequests, pr)
+			if filter != nil && filter.Limit > 0 && len(pullRequests) >= filter.Limit {
+				done = true
+				break
+			}
+		}
+
+		if page.IsLastPage {
+			done = true
+		}
+		start = page.NextPageStart
+	}
+
+	var prs []PullRequest
+	for _, bp := range pullRequests {
+		pr := PullRequest{
+			Number:      bp.ID,
+			Title:       bp.Title,
+			State:       bitbucketState(bp.State),
+			CreatedAt:   bitbucketTime(bp.CreatedDate),
+			BaseRefName: bp.ToRef.DisplayID,
+			Body:        bp.Description,
+		}
+		if bp.ClosedDate != 0 {
+			closedAt := bitbucketTime(bp.ClosedDate)
+			pr.ClosedAt = &closedAt
+			if pr.State == stateMerged {
+				pr.MergedAt = &closedAt
+			}
+		}
+		pr.Author.Login = bp.Author.User.Slug
+		if len(bp.Links.Self) > 0 {
+			pr.URL = bp.Links.Self[0].Href
+		} else {
+			pr.URL = fmt.Sprintf("%s/projects/%s/repos/%s/pull-requests/%d/overview", repository.BaseURL, repository.Owner, repository.Name, bp.ID)
+		}
+		prs = append(prs, pr)
+	}
+	prs = filterByPeriod(prs, filter)
+
+	// Activities carry who approved, who requested changes and who merged, with timestamps; changes list
+	// the files. A pull request whose details cannot be fetched is reported as unverified.
+	for i := range prs {
+		ref := fmt.Sprintf("%s/%s#%d", repository.Owner, repository.Name, prs[i].Number)
+		activities, err := bc.fetchActivities(repository.BaseURL, fmt.Sprintf("%s/pull-requests/%d/activities", repoPath, prs[i].Number))
+		if err != nil {
+			markUnverified(&prs[i], ref, partReviews, err)
+		}
+
+		// Activities are returned newest first; reviews are listed in the order they happened
+		for j := len(activities) - 1; j >= 0; j-- {
+			activity := activities[j]
+			review := Review{Author: Actor{Login: activity.User.Slug}, SubmittedAt: bitbucketTime(activity.CreatedDate)}
+			switch activity.Action {
+			case "APPROVED":
+				review.State = "APPROVED"
+			case "REVIEWED":
+				// "Needs work"
+				review.State = "CHANGES_REQUESTED"
+			case "UNAPPROVED":
+				// A withdrawn approval no longer counts, like a dismissed GitHub review
+				for k := range prs[i].Reviews {
+					if prs[i].Reviews[k].Author.Login == activity.User.Slug && prs[i].Reviews[k].State == "APPROVED" {
+						prs[i].Reviews[k].State = "DISMISSED"
+					}
+				}
+				continue
+			case "MERGED":
+				prs[i].MergedBy = &Actor{Login: activity.User.Slug}
+				continue
+			default:
+				continue
+			}
+			prs[i].Reviews = append(prs[i].Reviews, review)
+		}
+
+		if err := bc.fetchChangedFiles(repository.BaseURL, fmt.Sprintf("%s/pull-requests/%d", repoPath, prs[i].Number), &prs[i]); err != nil {
+			markUnverified(&prs[i], ref, partFiles, err)
+		}
+
+		commits, err := fetchBitbucketPages[bitbucketCommit](bc, repository.BaseURL, fmt.Sprintf("%s/pull-requests/%d/commits", repoPath, prs[i].Number))
+		if err != nil {
+			markUnverified(&prs[i], ref, partCommits, err)
+		}
+		// Bitbucket lists pull request commits newest first
+		for j := len(commits) - 1; j >= 0; j-- {
+			headline, body, _ := strings.Cut(strings.TrimSpace(commits[j].Message), "\n")
+			prs[i].Commits = append(prs[i].Commits, PRCommit{
+				Oid:             commits[j].ID,
+				MessageHeadline: headline,
+				MessageBody:     strings.TrimSpace(body),
+				AuthoredDate:    bitbucketTime(commits[j].AuthorTimestamp),
+				CommittedDate:   bitbucketTime(commits[j].CommitterTimestamp),
+			})
+		}
+	}
+
+	return prs, nil
+}
+
+// fetchChangedFiles sets the changed files of a pull request from its changes and their line
+// counts from its diff. Files past Bitbucket's change limit are flagged as incomplete.
+func (bc *BitbucketClient) fetchChangedFiles(baseURL, prPath string, pr *PullRequest) error {
+	changes, err := fetchBitbucketPages[bitbucketChange](bc, baseURL, prPath+"/changes")
+	if err != nil {
+		return err
+	}
+	for _, change := range changes {
+		pr.Files = append(pr.Files, ChangedFile{Path: change.Path.ToString})
+	}
+	pr.ChangedFiles = len(pr.Files)
+	pr.FilesIncomplete = len(changes) >= maxBitbucketChanges
+
+	// Line counts come from the diff, which Bitbucket truncates on very large pull requests
+	var diff bitbucketDiff
+	if err := bc.get(baseURL, prPath+"/diff?contextLines=0", &diff); err != nil {
+		return err
+	}
+	applyBitbucketDiff(pr, diff)
+	return nil
+}
+
+// applyBitbucketDiff adds the added and removed lines of a pull request diff to its files and
+// totals. Line counts are unknown when the diff was truncated or leaves out changed files.
+func applyBitbucketDiff(pr *PullRequest, diff bitbucketDiff) {
+	diffed := make(map[string]bool)
+	for _, fileDiff := range diff.Diffs {
+		path := ""
+		if fileDiff.Destination != nil {
+			path = fileDiff.Destination.ToString
+		} else if fileDiff.Source != nil {
+			path = fileDiff.Source.ToString
+		}
+		diffed[path] = true
+
+		var additions, deletions int
+		for _, hunk := range fileDiff.Hunks {
+			for _, segment := range hunk.Segments {
+				switch segment.Type {
+				case "ADDED":
+					additions += len(segment.Lines)
+				case "REMOVED":
+					deletions += len(segment.Lines)
+				}
+			}
+		}
+		pr.Additions += additions
+		pr.Deletions += deletions
+		for k := range pr.Files {
+			if pr.Files[k].Path == path {
+				pr.Files[k].Additions, pr.Files[k].Deletions = additions, deletions
+			}
+		}
+		if fileDiff.Truncated {
+			pr.LinesUnknown = true
+		}
+	}
+	if diff.Truncated {
+		pr.LinesUnknown = true
+	}
+	for _, file := range pr.Files {
+		if !diffed[file.Path] {
+			pr.LinesUnknown = true
+		}
+	}
+}
+
+// fetchActivities returns every activity of a pull request
+func (bc *BitbucketClient) fetchActivities(baseURL, path string) ([]bitbucketActivity, error) {
+	return fetchBitbucketPages[bitbucketActivity](bc, baseURL, path)
+}
+
+// fetchBitbucketPages returns every value of a paged Bitbucket Server API response
+func fetchBitbucketPages[T any](bc *BitbucketClient, baseURL, path string) ([]T, error) {
+	var values []T
+	for start := 0; ; {
+		var page bitbucketPage[T]
+		if err := bc.get(baseURL, fmt.Sprintf("%s?limit=%d&start=%d", path, maxBitbucketPageSize, start), &page); err != nil {
+			return nil, err
+		}
+		values = append(values, page.Values...)
+		if page.IsLastPage {
+			return values, nil
+		}
+		if len(page.Values) == 0 {
+			return nil, fmt.Errorf("%s stopped before its last page", path)
+		}
+		start = page.NextPageStart
+	}
+}
+
+// get performs an authenticated GET against the Bitbucket Server REST API and decodes the JSON response
+func (bc *BitbucketClient) get(baseURL, path string, result interface{}) error {
+	header := http.Header{}
+	if bc.token != "" {
+		header.Set("Authorization", "Bearer "+bc.token)
+	}
+	_, err := getJSON(bc.httpClient, strings.TrimSuffix(baseURL, "/")+"/rest/api/1.0"+path, header, result)
+	return err
+}
+
+// bitbucketTime converts a Bitbucket millisecond timestamp to a time
+func bitbucketTime(ms int64) time.Time {
+	return time.UnixMilli(ms).UTC()
+}
+
+// bitbucketQueryState returns the pull request state parameter covering the requested states
+func bitbucketQueryState(filter *PRFilter) string {
+	states := queryStates(filter)
+	if len(states) > 1 {
+		return "ALL"
+	}
+	switch states[0] {
+	case stateClosed:
+		return "DECLINED"
+	case stateOpen:
+		return "OPEN"
+	default:
+		return "MERGED"
+	}
+}
+
+// bitbucketState maps Bitbucket Server pull request states to the GitHub states used in reports
+func bitbucketState(state string) string {
+	if state == "DECLINED" {
+		return stateClosed
+	}
+	return state
+}
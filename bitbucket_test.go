@@ -0,0 +1,187 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const bitbucketTestRepo = "/rest/api/1.0/projects/ACME/repos/api/pull-requests"
+
+// bitbucketTestResponses returns a repository with two merged pull requests listed over two pages
+func bitbucketTestResponses() map[string]fakeResponse {
+	return map[string]fakeResponse{
+		bitbucketTestRepo + "?limit=1000&order=NEWEST&start=0&state=MERGED": {
+			Body: `{"isLastPage": false, "nextPageStart": 1, "values": [{"id": 7, "title": "Add billing", "state": "MERGED",
+				"createdDate": 1709200000000, "closedDate": 1709290000000, "author": {"user": {"slug": "alice"}},
+				"toRef": {"displayId": "main"}, "links": {"self": [{"href": "https://bitbucket.example.com/pr/7"}]}}]}`,
+		},
+		bitbucketTestRepo + "?limit=1000&order=NEWEST&start=1&state=MERGED": {
+			Body: `{"isLastPage": true, "values": [{"id": 6, "title": "Initial", "state": "MERGED",
+				"createdDate": 1709000000000, "closedDate": 1709100000000, "author": {"user": {"slug": "carol"}}}]}`,
+		},
+		bitbucketTestRepo + "/7/activities?limit=1000&start=0": {
+			Body: `{"isLastPage": true, "values": [
+				{"action": "MERGED", "createdDate": 1709290000000, "user": {"slug": "bob"}},
+				{"action": "APPROVED", "createdDate": 1709280000000, "user": {"slug": "carol"}},
+				{"action": "UNAPPROVED", "createdDate": 1709270000000, "user": {"slug": "dave"}},
+				{"action": "APPROVED", "createdDate": 1709260000000, "user": {"slug": "dave"}}]}`,
+		},
+		bitbucketTestRepo + "/6/activities?limit=1000&start=0": {
+			Body: `{"isLastPage": true, "values": [{"action": "MERGED", "createdDate": 1709100000000, "user": {"slug": "carol"}}]}`,
+		},
+		bitbucketTestRepo + "/7/changes?limit=1000&start=0": {
+			Body: `{"isLastPage": false, "nextPageStart": 1, "values": [{"path": {"toString": "app.go"}}]}`,
+		},
+		bitbucketTestRepo + "/7/changes?limit=1000&start=1": {
+			Body: `{"isLastPage": true, "values": [{"path": {"toString": "gone.go"}}]}`,
+		},
+		bitbucketTestRepo + "/6/changes?limit=1000&start=0": {
+			Body: `{"isLastPage": true, "values": [{"path": {"toString": "README.md"}}]}`,
+		},
+		bitbucketTestRepo + "/7/diff?contextLines=0": {
+			Body: `{"diffs": [
+				{"source": {"toString": "app.go"}, "destination": {"toString": "app.go"},
+				 "hunks": [{"segments": [{"type": "REMOVED", "lines": [{}]}, {"type": "ADDED", "lines": [{}, {}]}]}]},
+				{"source": {"toString": "gone.go"},
+				 "hunks": [{"segments": [{"type": "REMOVED", "lines": [{}, {}, {}]}]}]}]}`,
+		},
+		bitbucketTestRepo + "/6/diff?contextLines=0": {
+			Body: `{"diffs": [], "truncated": true}`,
+		},
+		bitbucketTestRepo + "/7/commits?limit=1000&start=0": {
+			Body: `{"isLastPage": true, "values": [{"id": "bbb", "message": "Second\n\nDetails"}, {"id": "aaa", "message": "First"}]}`,
+		},
+		bitbucketTestRepo + "/6/commits?limit=1000&start=0": {
+			Body: `{"isLastPage": true, "values": [{"id": "ccc", "message": "Initial"}]}`,
+		},
+	}
+}
+
+func TestBitbucketFetchPullRequests(t *testing.T) {
+	server := newFakeAPI(t, bitbucketTestResponses())
+	client := &BitbucketClient{httpClient: server.Client()}
+
+	prs, err := client.FetchPullRequests(Repository{Owner: "ACME", Name: "api", BaseURL: server.URL}, nil, nil)
+	if err != nil {
+		t.Fatalf("FetchPullRequests: %v", err)
+	}
+	if got := pullRequestNumbers(prs); !reflect.DeepEqual(got, []int{7, 6}) {
+		t.Fatalf("numbers = %v, want [7 6] from both pages", got)
+	}
+
+	pr := prs[0]
+	if pr.State != stateMerged || pr.Author.Login != "alice" || mergedByLogin(pr) != "bob" || pr.URL != "https://bitbucket.example.com/pr/7" {
+		t.Errorf("mapped state %q, author %q, merged by %q, URL %q", pr.State, pr.Author.Login, mergedByLogin(pr), pr.URL)
+	}
+	if got := approvers(pr); !reflect.DeepEqual(got, []string{"carol"}) {
+		t.Errorf("approvers = %v, want [carol] without the withdrawn approval", got)
+	}
+	if got := filePaths(pr.Files); !reflect.DeepEqual(got, []string{"app.go", "gone.go"}) {
+		t.Errorf("files = %v, want both change pages", got)
+	}
+	if pr.Additions != 2 || pr.Deletions != 4 || pr.LinesUnknown || pr.FilesIncomplete {
+		t.Errorf("size +%d/-%d (lines unknown %v, files incomplete %v), want +2/-4 complete",
+			pr.Additions, pr.Deletions, pr.LinesUnknown, pr.FilesIncomplete)
+	}
+	if got := commitOids(pr.Commits); !reflect.DeepEqual(got, []string{"aaa", "bbb"}) {
+		t.Errorf("commits = %v, want oldest first", got)
+	}
+
+	if !prs[1].LinesUnknown {
+		t.Errorf("pull request with a truncated diff has known line counts")
+	}
+}
+
+func TestApplyBitbucketDiff(t *testing.T) {
+	tests := []struct {
+		name         string
+		files        []string
+		diff         string
+		linesUnknown bool
+	}{
+		{"complete", []string{"a.go"}, `{"diffs": [{"destination": {"toString": "a.go"}}]}`, false},
+		{"truncated diff", []string{"a.go"}, `{"diffs": [{"destination": {"toString": "a.go"}}], "truncated": true}`, true},
+		{"truncated file", []string{"a.go"}, `{"diffs": [{"destination": {"toString": "a.go"}, "truncated": true}]}`, true},
+		{"file left out", []string{"a.go", "b.go"}, `{"diffs": [{"destination": {"toString": "a.go"}}]}`, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var diff bitbucketDiff
+			if err := decodeTestJSON(tt.diff, &diff); err != nil {
+				t.Fatal(err)
+			}
+			var pr PullRequest
+			for _, path := range tt.files {
+				pr.Files = append(pr.Files, ChangedFile{Path: path})
+			}
+			applyBitbucketDiff(&pr, diff)
+			if pr.LinesUnknown != tt.linesUnknown {
+				t.Errorf("lines unknown = %v, want %v", pr.LinesUnknown, tt.linesUnknown)
+			}
+		})
+	}
+}
+
+func TestBitbucketFetchPullRequestsFlagsChangeLimit(t *testing.T) {
+	var values []string
+	for i := 0; i < maxBitbucketChanges; i++ {
+		values = append(values, fmt.Sprintf(`{"path": {"toString": "file%d.txt"}}`, i))
+	}
+	responses := bitbucketTestResponses()
+	responses[bitbucketTestRepo+"/6/changes?limit=1000&start=0"] = fakeResponse{
+		Body: `{"isLastPage": true, "values": [` + strings.Join(values, ",") + `]}`,
+	}
+	server := newFakeAPI(t, responses)
+	client := &BitbucketClient{httpClient: server.Client()}
+
+	prs, err := client.FetchPullRequests(Repository{Owner: "ACME", Name: "api", BaseURL: server.URL}, nil, nil)
+	if err != nil {
+		t.Fatalf("FetchPullRequests: %v", err)
+	}
+	if !prs[1].FilesIncomplete || prs[0].FilesIncomplete {
+		t.Errorf("files incomplete = %v and %v, want only the PR at the change limit", prs[0].FilesIncomplete, prs[1].FilesIncomplete)
+	}
+}
+
+func TestBitbucketFetchPullRequestsMarksFailedDetailsUnverified(t *testing.T) {
+	tests := []struct {
+		name string
+		path string
+		part string
+	}{
+		{"activities", "/7/activities?limit=1000&start=0", partReviews},
+		{"changes", "/7/changes?limit=1000&start=1", partFiles},
+		{"diff", "/7/diff?contextLines=0", partFiles},
+		{"commits", "/7/commits?limit=1000&start=0", partCommits},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			responses := bitbucketTestResponses()
+			responses[bitbucketTestRepo+tt.path] = fakeResponse{Status: http.StatusInternalServerError}
+			server := newFakeAPI(t, responses)
+			client := &BitbucketClient{httpClient: server.Client()}
+
+			prs, err := client.FetchPullRequests(Repository{Owner: "ACME", Name: "api", BaseURL: server.URL}, nil, nil)
+			if err != nil {
+				t.Fatalf("FetchPullRequests failed for the whole repository: %v", err)
+			}
+			if len(prs) != 2 || len(prs[1].Unverified) != 0 {
+				t.Fatalf("got %d pull requests, want 2 with only #7 unverified", len(prs))
+			}
+			if len(prs[0].Unverified) != 1 || !strings.HasPrefix(prs[0].Unverified[0], tt.part) {
+				t.Errorf("unverified = %v, want the %s", prs[0].Unverified, tt.part)
+			}
+			if tt.part == partReviews && !prs[0].ReviewsUnknown {
+				t.Errorf("reviews are known after the activities failed")
+			}
+			if tt.part == partFiles && !prs[0].FilesIncomplete {
+				t.Errorf("files are complete after the %s failed", tt.name)
+			}
+		})
+	}
+}
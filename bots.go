@@ -0,0 +1,115 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"regexp"
+	"strings"
+)
+
+var excludeBots bool
+
+// Author types
+const (
+	authorHuman = "Human"
+	authorBot   = "Bot"
+)
+
+// defaultBotPatterns match GitHub App accounts as reported by the API ("dependabot[bot]") and by gh ("app/dependabot")
+var defaultBotPatterns = []string{"*[bot]", "app/*"}
+
+// botPatterns are the compiled login patterns of bot accounts
+var botPatterns = compileBotPatterns(nil)
+
+// compileBotPatterns turns case-insensitive login patterns, where * matches any characters,
+// into regular expressions, always including the default patterns
+func compileBotPatterns(patterns []string) []*regexp.Regexp {
+	var compiled []*regexp.Regexp
+	for _, pattern := range append(append([]string{}, defaultBotPatterns...), patterns...) {
+		expr := strings.ReplaceAll(regexp.QuoteMeta(strings.TrimSpace(pattern)), `\*`, ".*")
+		compiled = append(compiled, regexp.MustCompile("(?i)^"+expr+"$"))
+	}
+	return compiled
+}
+
+// loadBotPatterns compiles the bot patterns of the repository configuration for subcommands
+// that do not otherwise load it. Without a configuration only the default patterns apply.
+func loadBotPatterns() {
+	config, err := LoadRepositories(reposFile)
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			log.Printf("⚠️ Could not read bot patterns from %s: %v", reposFile, err)
+		}
+		return
+	}
+	botPatterns = compileBotPatterns(config.Bots)
+}
+
+// isBotLogin reports whether a login belongs to a bot account by pattern or identity mapping
+func isBotLogin(login string) bool {
+	if login == "" {
+		return false
+	}
+	if identity, ok := lookupIdentity(login); ok && identity.Bot {
+		return true
+	}
+	for _, pattern := range botPatterns {
+		if pattern.MatchString(login) {
+			return true
+		}
+	}
+	return false
+}
+
+// isBotAuthored reports whether a pull request was opened by a bot
+func isBotAuthored(pr PullRequest) bool {
+	return pr.Author.IsBot || isBotLogin(pr.Author.Login)
+}
+
+// authorType returns the author type shown in reports
+func authorType(pr PullRequest) string {
+	if isBotAuthored(pr) {
+		return authorBot
+	}
+	return authorHuman
+}
+
+// humanApprovers returns the approvers that are not bot accounts
+func humanApprovers(pr PullRequest) []string {
+	var logins []string
+	for _, login := range approvers(pr) {
+		if !isBotLogin(login) {
+			logins = append(logins, login)
+		}
+	}
+	return logins
+}
+
+// splitBotRecords separates bot-authored records from the rest
+func splitBotRecords(records []PRRecord) ([]PRRecord, []PRRecord) {
+	var human, automated []PRRecord
+	for _, item := range records {
+		if isBotAuthored(item.PR) {
+			automated = append(automated, item)
+		} else {
+			human = append(human, item)
+		}
+	}
+	return human, automated
+}
+
+// generateAutomatedSection lists bot-authored PRs separately from the human changes
+func generateAutomatedSection(output *os.File, automated []PRRecord) {
+	repoPRs := make(map[string][]PRRecord)
+	for _, item := range automated {
+		repoPRs[item.RepositoryKey()] = append(repoPRs[item.RepositoryKey()], item)
+	}
+
+	fmt.Fprintf(output, "## 🤖 Automated Changes\n\n")
+	fmt.Fprintf(output, "- **Bot-Authored Pull Requests:** %d\n\n", len(automated))
+	for _, repo := range sortedKeys(repoPRs) {
+		generateRepositorySection(output, "###", repo, repoPRs[repo])
+	}
+}
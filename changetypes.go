@@ -0,0 +1,106 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// changeTypeStandard is the change type of PRs that match no configured category
+const changeTypeStandard = "standard"
+
+// changeCategory is a configured change type with its parsed retrospective settings
+type changeCategory struct {
+	ChangeType
+	window time.Duration
+	ticket *regexp.Regexp
+}
+
+// changeCategories holds the compiled change types in configuration order
+var changeCategories []changeCategory
+
+// compileChangeTypes validates the configured change types and parses their windows and ticket patterns
+func compileChangeTypes(types []ChangeType) ([]changeCategory, error) {
+	var categories []changeCategory
+	for _, changeType := range types {
+		if changeType.Name == "" {
+			return nil, fmt.Errorf("change type without a name")
+		}
+		category := changeCategory{ChangeType: changeType}
+		if changeType.RetrospectiveWindow != "" {
+			window, err := time.ParseDuration(changeType.RetrospectiveWindow)
+			if err != nil || window <= 0 {
+				return nil, fmt.Errorf("change type %s: invalid retrospective_window %q", changeType.Name, changeType.RetrospectiveWindow)
+			}
+			category.window = window
+		}
+		if changeType.TicketPattern != "" {
+			ticket, err := regexp.Compile(changeType.TicketPattern)
+			if err != nil {
+				return nil, fmt.Errorf("change type %s: invalid ticket_pattern: %w", changeType.Name, err)
+			}
+			category.ticket = ticket
+		}
+		categories = append(categories, category)
+	}
+	return categories, nil
+}
+
+// classifyChanges sets the change type of every pull request from its labels and title
+func classifyChanges(records []PRRecord) {
+	for i := range records {
+		records[i].PR.ChangeType = changeTypeStandard
+		if category := matchChangeCategory(records[i].PR); category != nil {
+			records[i].PR.ChangeType = category.Name
+		}
+	}
+}
+
+// matchChangeCategory returns the first configured change type matching a PR label
+// (case-insensitive) or title prefix
+func matchChangeCategory(pr PullRequest) *changeCategory {
+	title := strings.ToLower(strings.TrimSpace(pr.Title))
+	for i := range changeCategories {
+		category := &changeCategories[i]
+		for _, label := range pr.Labels {
+			for _, name := range category.Labels {
+				if strings.EqualFold(label.Name, name) {
+					return category
+				}
+			}
+		}
+		for _, prefix := range category.TitlePrefixes {
+			if prefix != "" && strings.HasPrefix(title, strings.ToLower(prefix)) {
+				return category
+			}
+		}
+	}
+	return nil
+}
+
+// changeCategoryOf returns the configured category a classified PR belongs to
+func changeCategoryOf(pr PullRequest) *changeCategory {
+	for i := range changeCategories {
+		if changeCategories[i].Name == pr.ChangeType {
+			return &changeCategories[i]
+		}
+	}
+	return nil
+}
+
+// retrospectiveTicket returns the first retrospective ticket reference in the PR description or reviews
+func retrospectiveTicket(pr PullRequest, category *changeCategory) string {
+	if category.ticket == nil {
+		return ""
+	}
+	if ticket := category.ticket.FindString(pr.Body); ticket != "" {
+		return ticket
+	}
+	for _, review := range pr.Reviews {
+		if ticket := category.ticket.FindString(review.Body); ticket != "" {
+			return ticket
+		}
+	}
+	return ""
+}
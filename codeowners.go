@@ -0,0 +1,355 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	checkCodeOwners bool
+	ownerTeamsFile  string
+)
+
+// codeOwnersLocations are the paths CODEOWNERS is read from, in GitHub's order of precedence
+var codeOwnersLocations = []string{".github/CODEOWNERS", "CODEOWNERS", "docs/CODEOWNERS"}
+
+// fileReader is implemented by providers that can read a repository file as of a commit;
+// the returned error wraps os.ErrNotExist when the file does not exist at that commit
+type fileReader interface {
+	ReadFile(repository Repository, ref, path string) ([]byte, error)
+}
+
+// teamLister is implemented by providers that can resolve team membership
+type teamLister interface {
+	TeamMembers(host, org, team string) ([]string, error)
+}
+
+// OwnerTeamsConfig is the local mapping of CODEOWNERS teams to member logins
+type OwnerTeamsConfig struct {
+	Teams map[string][]string `yaml:"teams"` // "@org/team" (or "org/team") to logins
+}
+
+// codeOwnersEntry is one rule of a CODEOWNERS file
+type codeOwnersEntry struct {
+	Pattern string
+	Owners  []string
+	match   *regexp.Regexp
+}
+
+var (
+	// ownerTeams holds the local team mapping keyed by lower-case "org/team"; nil when not given
+	ownerTeams map[string][]string
+
+	// teamCache memoizes team memberships looked up through the API, keyed by host and team;
+	// teamCacheMu only guards the map, so lookups of different teams run concurrently
+	teamCache   = make(map[string]*teamLookup)
+	teamCacheMu sync.Mutex
+)
+
+// teamLookup is the result of looking up a team's members once
+type teamLookup struct {
+	once    sync.Once
+	members []string
+	err     error
+}
+
+// loadOwnerTeams loads the --owner-teams mapping file, if given
+func loadOwnerTeams() {
+	if ownerTeamsFile == "" || ownerTeams != nil {
+		return
+	}
+	data, err := os.ReadFile(ownerTeamsFile)
+	if err != nil {
+		log.Fatalf("Failed to read owner teams file: %v", err)
+	}
+	var config OwnerTeamsConfig
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		log.Fatalf("Failed to parse owner teams file %s: %v", ownerTeamsFile, err)
+	}
+	ownerTeams = make(map[string][]string)
+	for team, members := range config.Teams {
+		ownerTeams[strings.ToLower(strings.TrimPrefix(team, "@"))] = members
+	}
+	fmt.Printf("👥 Loaded %d owner teams from %s\n", len(ownerTeams), ownerTeamsFile)
+}
+
+// resolveCodeOwners reads CODEOWNERS as of each merged PR's merge commit and records the rules that
+// own its changed files; PRs that cannot be fully checked are marked with the reason
+func resolveCodeOwners(config *RepositoriesConfig, records []PRRecord) {
+	providers := NewProviders()
+	unsupported := make(map[string]bool)
+	var unverified, withOwners int
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	semaphore := make(chan struct{}, max(maxWorkers, 1))
+	for i := range records {
+		item := &records[i]
+		if prState(item.PR) != stateMerged {
+			continue
+		}
+		if item.PR.MergeCommit == nil || item.PR.MergeCommit.Oid == "" || len(item.PR.Files) == 0 {
+			item.PR.CodeOwnersUnverified = "no changed files or merge commit to check CODEOWNERS against"
+			unverified++
+			continue
+		}
+
+		repository := recordRepository(config, *item)
+		reader, ok := providers[repository.Provider].(fileReader)
+		if !ok {
+			if !unsupported[repository.Provider] {
+				unsupported[repository.Provider] = true
+				log.Printf("⚠️ CODEOWNERS verification is not supported for %s repositories", repository.Provider)
+			}
+			item.PR.CodeOwnersUnverified = fmt.Sprintf("CODEOWNERS cannot be read from %s repositories", repository.Provider)
+			unverified++
+			continue
+		}
+
+		wg.Add(1)
+		semaphore <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-semaphore }()
+
+			entries, err := readCodeOwners(reader, repository, item.PR.MergeCommit.Oid)
+			if err != nil {
+				log.Printf("⚠️ Could not read CODEOWNERS for %s#%d: %v", item.Repository, item.PR.Number, err)
+				item.PR.CodeOwnersUnverified = fmt.Sprintf("CODEOWNERS could not be read at %s", shortSHA(item.PR.MergeCommit.Oid))
+				mu.Lock()
+				unverified++
+				mu.Unlock()
+				return
+			}
+			item.PR.CodeOwners = codeOwnersFor(entries, item.PR.Files)
+			if incompleteFiles(item.PR) {
+				// The owners of the files that were listed still count; the rest are unknown
+				item.PR.CodeOwnersUnverified = fmt.Sprintf("the changed file list is incomplete (%d listed)", len(item.PR.Files))
+			}
+
+			mu.Lock()
+			if len(item.PR.CodeOwners) > 0 {
+				withOwners++
+			}
+			if item.PR.CodeOwnersUnverified != "" {
+				unverified++
+			}
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+
+	fmt.Printf("👥 CODEOWNERS: %d PRs changed owned files", withOwners)
+	if unverified > 0 {
+		fmt.Printf(", %d PRs could not be fully checked", unverified)
+	}
+	fmt.Printf("\n")
+}
+
+// incompleteFiles reports whether a PR's changed file list is known to leave files out: the
+// provider said so, or it lists fewer files than the PR changed, as gh does past 100 files
+func incompleteFiles(pr PullRequest) bool {
+	return pr.FilesIncomplete || len(pr.Files) < pr.ChangedFiles
+}
+
+// readCodeOwners reads and parses the CODEOWNERS file in effect at a commit
+func readCodeOwners(reader fileReader, repository Repository, ref string) ([]codeOwnersEntry, error) {
+	for _, location := range codeOwnersLocations {
+		content, err := reader.ReadFile(repository, ref, location)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		return parseCodeOwners(string(content)), nil
+	}
+	return nil, nil
+}
+
+// parseCodeOwners parses CODEOWNERS rules, skipping comments, blank lines and section headers
+func parseCodeOwners(content string) []codeOwnersEntry {
+	var entries []codeOwnersEntry
+	for _, line := range strings.Split(content, "\n") {
+		if i := strings.Index(line, "#"); i >= 0 && (i == 0 || line[i-1] != '\\') {
+			line = line[:i]
+		}
+		fields := strings.Fields(strings.ReplaceAll(line, `\#`, "#"))
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "[") || strings.HasPrefix(fields[0], "^[") {
+			continue
+		}
+		match, err := pathPatternRegexp(fields[0])
+		if err != nil {
+			continue
+		}
+		entries = append(entries, codeOwnersEntry{Pattern: fields[0], Owners: fields[1:], match: match})
+	}
+	return entries
+}
+
+// codeOwnersFor returns the rules owning the changed files; the last matching rule wins and a
+// matching rule without owners leaves the file unowned
+func codeOwnersFor(entries []codeOwnersEntry, files []ChangedFile) []CodeOwnerRule {
+	seen := make(map[string]bool)
+	var rules []CodeOwnerRule
+	for _, file := range files {
+		for i := len(entries) - 1; i >= 0; i-- {
+			entry := entries[i]
+			if !entry.match.MatchString(file.Path) {
+				continue
+			}
+			if len(entry.Owners) > 0 && !seen[entry.Pattern] {
+				seen[entry.Pattern] = true
+				rules = append(rules, CodeOwnerRule{Pattern: entry.Pattern, Owners: entry.Owners})
+			}
+			break
+		}
+	}
+	return rules
+}
+
+// pathPatternRegexp compiles a gitignore-style path pattern as used by CODEOWNERS: a leading or
+// inner slash anchors it to the repository root, * and ? stay within a directory, ** spans
+// directories, and a pattern naming a directory matches everything below it
+func pathPatternRegexp(pattern string) (*regexp.Regexp, error) {
+	anchored := strings.Contains(strings.TrimSuffix(pattern, "/"), "/")
+	pattern = strings.TrimPrefix(pattern, "/")
+	dirOnly := strings.HasSuffix(pattern, "/")
+	pattern = strings.TrimSuffix(pattern, "/")
+	if pattern == "" {
+		return nil, fmt.Errorf("empty path pattern")
+	}
+
+	var expr strings.Builder
+	if anchored {
+		expr.WriteString("^")
+	} else {
+		expr.WriteString("^(?:.*/)?")
+	}
+	for i := 0; i < len(pattern); i++ {
+		switch {
+		case strings.HasPrefix(pattern[i:], "**/"):
+			expr.WriteString("(?:.*/)?")
+			i += 2
+		case strings.HasPrefix(pattern[i:], "**"):
+			expr.WriteString(".*")
+			i++
+		case pattern[i] == '*':
+			expr.WriteString("[^/]*")
+		case pattern[i] == '?':
+			expr.WriteString("[^/]")
+		default:
+			expr.WriteString(regexp.QuoteMeta(pattern[i : i+1]))
+		}
+	}
+	// A directory matches everything below it; a wildcard in the last segment only matches
+	// entries of its own directory, as in gitignore
+	last := pattern[strings.LastIndex(pattern, "/")+1:]
+	switch {
+	case dirOnly:
+		expr.WriteString("/.*$")
+	case strings.ContainsAny(last, "*?"):
+		expr.WriteString("$")
+	default:
+		expr.WriteString("(?:/.*)?$")
+	}
+	return regexp.Compile(expr.String())
+}
+
+// codeOwnerApproved reports whether a login belongs to a CODEOWNERS owner: the user itself,
+// a member of the team, or the person with that email in the identity mapping. It returns an
+// error when the members of a team owner could not be looked up.
+func codeOwnerApproved(item PRRecord, owner, login string) (bool, error) {
+	switch {
+	case strings.HasPrefix(owner, "@") && strings.Contains(owner, "/"):
+		members, err := teamMembers(item, strings.TrimPrefix(owner, "@"))
+		if err != nil {
+			return false, err
+		}
+		for _, member := range members {
+			if strings.EqualFold(member, login) {
+				return true, nil
+			}
+		}
+		return false, nil
+	case strings.HasPrefix(owner, "@"):
+		return strings.EqualFold(strings.TrimPrefix(owner, "@"), login), nil
+	default:
+		identity, ok := lookupIdentity(login)
+		return ok && identity.Email != "" && strings.EqualFold(identity.Email, owner), nil
+	}
+}
+
+// teamMembers returns the members of an "org/team" owner from the local mapping, or from
+// the provider API when the team is not mapped
+func teamMembers(item PRRecord, team string) ([]string, error) {
+	if members, ok := ownerTeams[strings.ToLower(team)]; ok {
+		return members, nil
+	}
+
+	key := normalizeHost(item.Host) + "/" + strings.ToLower(team)
+	teamCacheMu.Lock()
+	lookup, ok := teamCache[key]
+	if !ok {
+		lookup = &teamLookup{}
+		teamCache[key] = lookup
+	}
+	teamCacheMu.Unlock()
+
+	// Failed lookups are cached too so each team is only looked up and reported once
+	lookup.once.Do(func() {
+		provider := item.Provider
+		if provider == "" {
+			provider = providerGitHub
+		}
+		lister, ok := NewProviders()[provider].(teamLister)
+		if !ok {
+			lookup.err = fmt.Errorf("team %s is not in the owner teams mapping", team)
+			return
+		}
+		org, slug, _ := strings.Cut(team, "/")
+		lookup.members, lookup.err = lister.TeamMembers(item.Host, org, slug)
+		if lookup.err != nil {
+			log.Printf("⚠️ Could not resolve CODEOWNERS team @%s: %v", team, lookup.err)
+		}
+	})
+	return lookup.members, lookup.err
+}
+
+// missingCodeOwners returns the rules of a PR that no human approver owns. Rules that no
+// approver is known to own but whose team members could not be looked up are not reported
+// missing; the lookup errors are returned instead, so the PR is reported as unverified.
+func missingCodeOwners(item PRRecord) ([]CodeOwnerRule, error) {
+	var missing []CodeOwnerRule
+	var errs []error
+	for _, rule := range item.PR.CodeOwners {
+		satisfied := false
+		var ruleErr error
+		for _, login := range humanApprovers(item.PR) {
+			for _, owner := range rule.Owners {
+				approved, err := codeOwnerApproved(item, owner, login)
+				if err != nil && ruleErr == nil {
+					ruleErr = fmt.Errorf("%s: %w", rule.Pattern, err)
+				}
+				if approved {
+					satisfied = true
+				}
+			}
+		}
+		switch {
+		case satisfied:
+		case ruleErr != nil:
+			errs = append(errs, ruleErr)
+		default:
+			missing = append(missing, rule)
+		}
+	}
+	return missing, errors.Join(errs...)
+}
@@ -0,0 +1,169 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestPathPatternRegexp(t *testing.T) {
+	tests := []struct {
+		pattern string
+		match   []string
+		noMatch []string
+	}{
+		{
+			pattern: "*.go",
+			match:   []string{"main.go", "cmd/tool/main.go"},
+			noMatch: []string{"main.go.orig", "README.md"},
+		},
+		{
+			pattern: "/build.sh",
+			match:   []string{"build.sh"},
+			noMatch: []string{"scripts/build.sh"},
+		},
+		{
+			pattern: "docs/*.md",
+			match:   []string{"docs/index.md"},
+			noMatch: []string{"docs/guide/setup.md", "site/docs/index.md"},
+		},
+		{
+			pattern: "apps",
+			match:   []string{"apps", "apps/web/main.go", "services/apps/main.go"},
+			noMatch: []string{"apps.go", "myapps/main.go"},
+		},
+		{
+			pattern: "config/",
+			match:   []string{"config/app.yaml", "deploy/config/app.yaml"},
+			noMatch: []string{"config", "configs/app.yaml"},
+		},
+		{
+			pattern: "**/migrations",
+			match:   []string{"migrations/001.sql", "db/migrations/001.sql", "a/b/migrations/002.sql"},
+			noMatch: []string{"db/migrations.sql"},
+		},
+		{
+			pattern: "src/**/test.go",
+			match:   []string{"src/test.go", "src/a/b/test.go"},
+			noMatch: []string{"lib/src/a/test.go"},
+		},
+		{
+			pattern: "secrets/**",
+			match:   []string{"secrets/key.pem", "secrets/prod/key.pem"},
+			noMatch: []string{"app/secrets/key.pem"},
+		},
+		{
+			pattern: "file?.txt",
+			match:   []string{"file1.txt", "dir/fileA.txt"},
+			noMatch: []string{"file10.txt", "file/.txt"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern, func(t *testing.T) {
+			expr, err := pathPatternRegexp(tt.pattern)
+			if err != nil {
+				t.Fatalf("pathPatternRegexp(%q): %v", tt.pattern, err)
+			}
+			for _, path := range tt.match {
+				if !expr.MatchString(path) {
+					t.Errorf("%q does not match %q (%s)", tt.pattern, path, expr)
+				}
+			}
+			for _, path := range tt.noMatch {
+				if expr.MatchString(path) {
+					t.Errorf("%q matches %q (%s)", tt.pattern, path, expr)
+				}
+			}
+		})
+	}
+}
+
+func TestParseCodeOwners(t *testing.T) {
+	content := `# Default owners
+*                   @acme/platform
+
+[Docs section]
+docs/               @acme/writers writer@example.com
+docs/generated/
+/api/**/*.proto     @acme/api @lead   # inline comment
+\#notes.md          @archivist
+`
+	entries := parseCodeOwners(content)
+
+	var patterns []string
+	for _, entry := range entries {
+		patterns = append(patterns, entry.Pattern)
+	}
+	wantPatterns := []string{"*", "docs/", "docs/generated/", "/api/**/*.proto", "#notes.md"}
+	if !reflect.DeepEqual(patterns, wantPatterns) {
+		t.Fatalf("patterns = %v, want %v", patterns, wantPatterns)
+	}
+	if owners := entries[3].Owners; !reflect.DeepEqual(owners, []string{"@acme/api", "@lead"}) {
+		t.Errorf("owners of %s = %v, want the owners before the comment", entries[3].Pattern, owners)
+	}
+
+	// The last matching rule wins, and a matching rule without owners leaves the file unowned
+	tests := []struct {
+		path string
+		want []CodeOwnerRule
+	}{
+		{"main.go", []CodeOwnerRule{{Pattern: "*", Owners: []string{"@acme/platform"}}}},
+		{"docs/index.md", []CodeOwnerRule{{Pattern: "docs/", Owners: []string{"@acme/writers", "writer@example.com"}}}},
+		{"docs/generated/api.md", nil},
+		{"api/v1/user.proto", []CodeOwnerRule{{Pattern: "/api/**/*.proto", Owners: []string{"@acme/api", "@lead"}}}},
+		{"#notes.md", []CodeOwnerRule{{Pattern: "#notes.md", Owners: []string{"@archivist"}}}},
+	}
+	for _, tt := range tests {
+		if got := codeOwnersFor(entries, []ChangedFile{{Path: tt.path}}); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("owners of %s = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestMissingCodeOwnersReportsFailedTeamLookups(t *testing.T) {
+	previous := ownerTeams
+	ownerTeams = map[string][]string{"acme/api": {"bob"}}
+	t.Cleanup(func() { ownerTeams = previous })
+
+	merged := time.Date(2024, 3, 2, 10, 0, 0, 0, time.UTC)
+	record := func(owners ...string) PRRecord {
+		item := PRRecord{
+			Repository: "acme/api",
+			Host:       "codeowners.test",
+			Provider:   providerLocal, // Cannot list teams, so unmapped teams fail to resolve
+			PR: PullRequest{
+				Number:     1,
+				State:      stateMerged,
+				MergedAt:   &merged,
+				Reviews:    []Review{{Author: Actor{Login: "bob"}, State: "APPROVED", SubmittedAt: merged.Add(-time.Hour)}},
+				CodeOwners: []CodeOwnerRule{{Pattern: "*.go", Owners: owners}},
+			},
+		}
+		item.PR.Author.Login = "alice"
+		return item
+	}
+
+	tests := []struct {
+		name       string
+		owners     []string
+		wantType   string
+		noneOfType string
+	}{
+		{"approved by a mapped team member", []string{"@acme/api"}, "", ExceptionNoCodeOwner},
+		{"not approved by the owner", []string{"@carol"}, ExceptionNoCodeOwner, ExceptionOwnersUnverified},
+		{"team lookup failed", []string{"@acme/unmapped"}, ExceptionOwnersUnverified, ExceptionNoCodeOwner},
+		{"team lookup failed but another owner approved", []string{"@acme/unmapped", "@bob"}, "", ExceptionOwnersUnverified},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exceptions := evaluateControls(record(tt.owners...))
+			if tt.wantType != "" && !hasException(exceptions, tt.wantType) {
+				t.Errorf("exceptions = %v, want %s", exceptions, tt.wantType)
+			}
+			if hasException(exceptions, tt.noneOfType) {
+				t.Errorf("exceptions = %v, want no %s", exceptions, tt.noneOfType)
+			}
+		})
+	}
+}
@@ -0,0 +1,162 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Column kinds control how report values are rendered in each output format
+const (
+	columnText = iota
+	columnLink
+	columnDate
+	columnNumber
+)
+
+// reportColumn describes one column of the pull request tables in the HTML and XLSX reports
+type reportColumn struct {
+	Header string
+	Kind   int
+	Width  float64                   // XLSX column width
+	Value  func(PRRecord) string     // Display value
+	Date   func(PRRecord) *time.Time // Typed value for columnDate columns
+}
+
+// prColumns lists the pull request columns shared by the tabular report outputs
+var prColumns = []reportColumn{
+	{Header: "Repository", Kind: columnText, Width: 30, Value: func(item PRRecord) string {
+		return item.RepositoryKey()
+	}},
+	{Header: "Verticals", Kind: columnText, Width: 20, Value: func(item PRRecord) string {
+		return strings.Join(item.Verticals, ", ")
+	}},
+	{Header: "PR_Number", Kind: columnLink, Width: 12, Value: func(item PRRecord) string {
+		return fmt.Sprintf("#%d", item.PR.Number)
+	}},
+	{Header: "Title", Kind: columnText, Width: 50, Value: func(item PRRecord) string {
+		return item.PR.Title
+	}},
+	{Header: "Change_Type", Kind: columnText, Width: 14, Value: func(item PRRecord) string {
+		return item.PR.ChangeType
+	}},
+	{Header: "Sensitive_Paths", Kind: columnText, Width: 40, Value: func(item PRRecord) string {
+		return strings.Join(item.PR.SensitivePaths, ", ")
+	}},
+	{Header: "Additions", Kind: columnNumber, Width: 12, Value: func(item PRRecord) string {
+		return lineCount(item.PR, item.PR.Additions)
+	}},
+	{Header: "Deletions", Kind: columnNumber, Width: 12, Value: func(item PRRecord) string {
+		return lineCount(item.PR, item.PR.Deletions)
+	}},
+	{Header: "Changed_Files", Kind: columnNumber, Width: 14, Value: func(item PRRecord) string {
+		return changeCount(item.PR, item.PR.ChangedFiles)
+	}},
+	{Header: "Commits", Kind: columnNumber, Width: 10, Value: func(item PRRecord) string {
+		if len(item.PR.Commits) == 0 {
+			return ""
+		}
+		return strconv.Itoa(len(item.PR.Commits))
+	}},
+	{Header: "Signed", Kind: columnText, Width: 10, Value: func(item PRRecord) string {
+		if !signaturesChecked(item.PR) {
+			return ""
+		}
+		if len(unsignedCommits(item.PR)) > 0 {
+			return "No"
+		}
+		return "Yes"
+	}},
+	{Header: "Risk", Kind: columnText, Width: 24, Value: func(item PRRecord) string {
+		return strings.Join(prRisks(item.PR), ", ")
+	}},
+	{Header: "Reverts", Kind: columnText, Width: 14, Value: func(item PRRecord) string {
+		if item.PR.IsRevert && len(item.PR.Reverts) == 0 {
+			return "unknown"
+		}
+		return prNumbers(item.PR.Reverts)
+	}},
+	{Header: "Reverted_By", Kind: columnText, Width: 14, Value: func(item PRRecord) string {
+		return prNumbers(item.PR.RevertedBy)
+	}},
+	{Header: "Author", Kind: columnText, Width: 20, Value: func(item PRRecord) string {
+		if item.PR.Author.Login == "" {
+			return "Unknown"
+		}
+		return personName(item.PR.Author.Login)
+	}},
+	{Header: "Author_Type", Kind: columnText, Width: 12, Value: func(item PRRecord) string {
+		return authorType(item.PR)
+	}},
+	{Header: "Author_Team", Kind: columnText, Width: 20, Value: func(item PRRecord) string {
+		return personTeam(item.PR.Author.Login)
+	}},
+	{Header: "Created_Date", Kind: columnDate, Width: 14, Value: func(item PRRecord) string {
+		return item.PR.CreatedAt.Format("2006-01-02")
+	}, Date: func(item PRRecord) *time.Time {
+		return &item.PR.CreatedAt
+	}},
+	{Header: "Merge_Date", Kind: columnDate, Width: 14, Value: func(item PRRecord) string {
+		if item.PR.MergedAt == nil {
+			return ""
+		}
+		return item.PR.MergedAt.Format("2006-01-02")
+	}, Date: func(item PRRecord) *time.Time {
+		return item.PR.MergedAt
+	}},
+	{Header: "Closed_Date", Kind: columnDate, Width: 14, Value: func(item PRRecord) string {
+		if item.PR.ClosedAt == nil {
+			return ""
+		}
+		return item.PR.ClosedAt.Format("2006-01-02")
+	}, Date: func(item PRRecord) *time.Time {
+		return item.PR.ClosedAt
+	}},
+	{Header: "Released_In", Kind: columnText, Width: 16, Value: func(item PRRecord) string {
+		return item.PR.ReleasedIn
+	}},
+	{Header: "Deployed_At", Kind: columnDate, Width: 14, Value: func(item PRRecord) string {
+		if item.PR.DeployedAt == nil {
+			return ""
+		}
+		return item.PR.DeployedAt.Format("2006-01-02")
+	}, Date: func(item PRRecord) *time.Time {
+		return item.PR.DeployedAt
+	}},
+	{Header: "Merged_By", Kind: columnText, Width: 20, Value: func(item PRRecord) string {
+		return personName(mergedByLogin(item.PR))
+	}},
+	{Header: "Approvers", Kind: columnText, Width: 30, Value: func(item PRRecord) string {
+		var names []string
+		for _, login := range approvers(item.PR) {
+			names = append(names, personName(login))
+		}
+		return strings.Join(names, ", ")
+	}},
+	{Header: "Source", Kind: columnText, Width: 12, Value: func(item PRRecord) string {
+		if item.PR.Provenance != "" {
+			return item.PR.Provenance
+		}
+		return item.Provider
+	}},
+	{Header: "Exceptions", Kind: columnText, Width: 30, Value: func(item PRRecord) string {
+		return exceptionTypes(item.PR)
+	}},
+}
+
+// changeCount renders a change volume count, left empty when the provider did not report sizes
+func changeCount(pr PullRequest, n int) string {
+	if !changeVolumeKnown(pr) {
+		return ""
+	}
+	return strconv.Itoa(n)
+}
+
+// lineCount renders a count of changed lines, left empty when the provider did not report them
+func lineCount(pr PullRequest, n int) string {
+	if !changedLinesKnown(pr) {
+		return ""
+	}
+	return strconv.Itoa(n)
+}
@@ -2,7 +2,9 @@ package main
 
 import (
 	"fmt"
+	"net/url"
 	"os"
+	"strings"
 
 	"gopkg.in/yaml.v3"
 )
@@ -21,15 +23,19 @@ func LoadRepositories(filename string) (*RepositoriesConfig, error) {
 		// Convert single-org multi-vertical format to full format
 		config := &RepositoriesConfig{
 			Organization: singleOrgMultiVerticalConfig.Organization,
+			Host:         singleOrgMultiVerticalConfig.Host,
+			Provider:     singleOrgMultiVerticalConfig.Provider,
+			BaseURL:      singleOrgMultiVerticalConfig.BaseURL,
+			Policy:       singleOrgMultiVerticalConfig.Policy,
 			Verticals:    []Vertical{},
 		}
 		
 		// Group repositories by vertical
 		verticalMap := make(map[string][]Repository)
 		for _, repoWithVerticals := range singleOrgMultiVerticalConfig.Repositories {
-			repo := Repository{
-				Owner: singleOrgMultiVerticalConfig.Organization,
-				Name:  repoWithVerticals.Name,
+			repo := repoWithVerticals.Repository
+			if repo.Owner == "" {
+				repo.Owner = singleOrgMultiVerticalConfig.Organization
 			}
 			
 			// Add repository to each of its verticals
@@ -46,6 +52,7 @@ func LoadRepositories(filename string) (*RepositoriesConfig, error) {
 			})
 		}
 		
+		applyRepositoryDefaults(config)
 		return config, nil
 	}
 
@@ -55,6 +62,10 @@ func LoadRepositories(filename string) (*RepositoriesConfig, error) {
 		// Convert single-org vertical format to full format
 		config := &RepositoriesConfig{
 			Organization: singleOrgVerticalConfig.Organization,
+			Host:         singleOrgVerticalConfig.Host,
+			Provider:     singleOrgVerticalConfig.Provider,
+			BaseURL:      singleOrgVerticalConfig.BaseURL,
+			Policy:       singleOrgVerticalConfig.Policy,
 			Verticals:    make([]Vertical, len(singleOrgVerticalConfig.Verticals)),
 		}
 		
@@ -72,6 +83,7 @@ func LoadRepositories(filename string) (*RepositoriesConfig, error) {
 			}
 		}
 		
+		applyRepositoryDefaults(config)
 		return config, nil
 	}
 
@@ -81,6 +93,10 @@ func LoadRepositories(filename string) (*RepositoriesConfig, error) {
 		// Convert single-org format to full format
 		config := &RepositoriesConfig{
 			Organization: singleOrgConfig.Organization,
+			Host:         singleOrgConfig.Host,
+			Provider:     singleOrgConfig.Provider,
+			BaseURL:      singleOrgConfig.BaseURL,
+			Policy:       singleOrgConfig.Policy,
 			Repositories: make([]Repository, len(singleOrgConfig.Repositories)),
 		}
 		
@@ -95,6 +111,7 @@ func LoadRepositories(filename string) (*RepositoriesConfig, error) {
 			return nil, fmt.Errorf("no repositories found in configuration file")
 		}
 		
+		applyRepositoryDefaults(config)
 		return config, nil
 	}
 
@@ -108,5 +125,104 @@ func LoadRepositories(filename string) (*RepositoriesConfig, error) {
 		return nil, fmt.Errorf("no repositories found in configuration file")
 	}
 
+	applyRepositoryDefaults(&config)
 	return &config, nil
 }
+
+// applyRepositoryDefaults fills in configuration-level defaults on every repository
+func applyRepositoryDefaults(config *RepositoriesConfig) {
+	apply := func(repo *Repository) {
+		if repo.Provider == "" {
+			repo.Provider = config.Provider
+		}
+		repo.Provider = strings.ToLower(strings.TrimSpace(repo.Provider))
+		if repo.Provider == "" {
+			repo.Provider = providerGitHub
+		}
+		if repo.BaseURL == "" {
+			repo.BaseURL = config.BaseURL
+		}
+		if repo.BaseURL == "" {
+			repo.BaseURL = defaultBaseURLs[repo.Provider]
+		}
+		repo.BaseURL = strings.TrimSuffix(repo.BaseURL, "/")
+
+		// Non-GitHub repositories are identified by the host of their base URL
+		if repo.Host == "" && repo.Provider != providerGitHub {
+			if parsed, err := url.Parse(repo.BaseURL); err == nil {
+				repo.Host = parsed.Host
+			}
+		}
+		if repo.Host == "" {
+			repo.Host = config.Host
+		}
+		repo.Host = normalizeHost(repo.Host)
+
+		repo.SensitivePaths = append(repo.SensitivePaths, config.SensitivePaths...)
+	}
+
+	for i := range config.Repositories {
+		apply(&config.Repositories[i])
+	}
+	for i := range config.Verticals {
+		for j := range config.Verticals[i].Repositories {
+			apply(&config.Verticals[i].Repositories[j])
+		}
+	}
+}
+
+// configuredRepository returns the configured repository with the given host and "owner/name"
+func configuredRepository(config *RepositoriesConfig, host, fullName string) (Repository, bool) {
+	matches := func(repo Repository) bool {
+		return repo.Host == host && strings.EqualFold(repo.Owner+"/"+repo.Name, fullName)
+	}
+	for _, repo := range config.Repositories {
+		if matches(repo) {
+			return repo, true
+		}
+	}
+	for _, vertical := range config.Verticals {
+		for _, repo := range vertical.Repositories {
+			if matches(repo) {
+				return repo, true
+			}
+		}
+	}
+	return Repository{}, false
+}
+
+// recordRepository returns the configured repository of a record, or a GitHub repository
+// derived from the record for input files that are not in the configuration
+func recordRepository(config *RepositoriesConfig, item PRRecord) Repository {
+	if repo, ok := configuredRepository(config, item.Host, item.Repository); ok {
+		return repo
+	}
+	owner, name, _ := strings.Cut(item.Repository, "/")
+	provider := item.Provider
+	if provider == "" {
+		provider = providerGitHub
+	}
+	return Repository{Owner: owner, Name: name, Host: item.Host, Provider: provider, SensitivePaths: config.SensitivePaths}
+}
+
+// configuredHosts returns the distinct GitHub hosts used by the configured repositories
+func configuredHosts(config *RepositoriesConfig) []string {
+	seen := make(map[string]bool)
+	var hosts []string
+	add := func(repo Repository) {
+		if repo.Provider == providerGitHub && !seen[repo.Host] {
+			seen[repo.Host] = true
+			hosts = append(hosts, repo.Host)
+		}
+	}
+
+	for _, repo := range config.Repositories {
+		add(repo)
+	}
+	for _, vertical := range config.Verticals {
+		for _, repo := range vertical.Repositories {
+			add(repo)
+		}
+	}
+	return hosts
+}
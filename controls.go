@@ -0,0 +1,217 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Exception types reported by the audit controls
+const (
+	ExceptionNoApproval       = "No Approval"
+	ExceptionNotVerified      = "Evidence Not Verified"
+	ExceptionUnmappedIdentity = "Unmapped Identity"
+	ExceptionFormerEmployee   = "Former Employee"
+	ExceptionNoHumanApproval  = "No Human Approval"
+	ExceptionNoRetrospective  = "Missing Retrospective Review"
+	ExceptionNoCodeOwner      = "No Code Owner Approval"
+	ExceptionTooFewApprovals  = "Insufficient Approvals"
+	ExceptionStaleApproval    = "Commits After Approval"
+	ExceptionUnsignedCommits  = "Unsigned Commits"
+	ExceptionOwnersUnverified = "Code Owners Not Verified"
+)
+
+// applyControls evaluates the audit controls for every pull request and records any exceptions on it
+func applyControls(records []PRRecord) {
+	for i := range records {
+		records[i].PR.Exceptions = evaluateControls(records[i])
+	}
+}
+
+// evaluateControls returns the control exceptions for a single merged pull request
+func evaluateControls(item PRRecord) []Exception {
+	pr := item.PR
+	if pr.MergedAt == nil {
+		return nil
+	}
+
+	var exceptions []Exception
+
+	switch category := changeCategoryOf(pr); {
+	case pr.ReviewsUnknown:
+		// Reviews that could not be listed are reported as unverified below rather than as missing
+	case category != nil && category.window > 0:
+		// Emergency-style changes may merge unreviewed but need a review or retro ticket soon after
+		if exception := evaluateRetrospective(pr, category); exception != nil {
+			exceptions = append(exceptions, *exception)
+		}
+	case len(approvers(pr)) == 0:
+		detail := fmt.Sprintf("merged by %s without an approving review from someone other than the author", mergedByLogin(pr))
+		if pr.Provenance == provenanceLocal {
+			detail = fmt.Sprintf("merged by %s; no approval is recorded in the local git history (Approved-by/Reviewed-by trailers)", mergedByLogin(pr))
+		}
+		exceptions = append(exceptions, Exception{
+			Type:   ExceptionNoApproval,
+			Detail: detail,
+		})
+	case len(humanApprovers(pr)) == 0:
+		// Automated approvals never satisfy the review control, including on bot-authored PRs
+		detail := fmt.Sprintf("approved only by bot accounts (%s)", strings.Join(approvers(pr), ", "))
+		if isBotAuthored(pr) {
+			detail = fmt.Sprintf("bot-authored PR %s", detail)
+		}
+		exceptions = append(exceptions, Exception{
+			Type:   ExceptionNoHumanApproval,
+			Detail: detail,
+		})
+	}
+
+	// Commits pushed after the newest approved commit were merged without re-review
+	if approval := approvalOfNewestCommit(pr.Author.Login, pr.Reviews, pr.MergedAt, pr.Commits); approval != nil {
+		if unreviewed := commitsAfterApproval(approval, pr.Commits); len(unreviewed) > 0 {
+			exceptions = append(exceptions, Exception{
+				Type: ExceptionStaleApproval,
+				Detail: fmt.Sprintf("%s added after the approval by %s on %s: %s", plural(len(unreviewed), "commit"),
+					approval.Author.Login, approval.SubmittedAt.Format("2006-01-02 15:04"), strings.Join(shortSHAs(unreviewed), ", ")),
+			})
+		}
+	}
+
+	// High-risk changes need more than one reviewer; unapproved PRs are already flagged above
+	if human := humanApprovers(pr); isHighRisk(pr) && len(human) > 0 && len(human) < sensitiveApprovals {
+		exceptions = append(exceptions, Exception{
+			Type: ExceptionTooFewApprovals,
+			Detail: fmt.Sprintf("changes sensitive paths (%s) with %d human approval(s); %d required",
+				strings.Join(pr.SensitivePaths, ", "), len(human), sensitiveApprovals),
+		})
+	}
+
+	// Every CODEOWNERS rule owning a changed file needs an approval from one of its owners
+	missing, ownersErr := missingCodeOwners(item)
+	if len(missing) > 0 {
+		var owned []string
+		for _, rule := range missing {
+			owned = append(owned, fmt.Sprintf("%s (%s)", rule.Pattern, strings.Join(rule.Owners, ", ")))
+		}
+		exceptions = append(exceptions, Exception{
+			Type:   ExceptionNoCodeOwner,
+			Detail: fmt.Sprintf("no approval from a code owner of %s", strings.Join(owned, "; ")),
+		})
+	}
+
+	// PRs whose owners could not be checked need their code owner approvals reviewed by hand
+	var ownersUnverified []string
+	if pr.CodeOwnersUnverified != "" {
+		ownersUnverified = append(ownersUnverified, pr.CodeOwnersUnverified)
+	}
+	if ownersErr != nil {
+		ownersUnverified = append(ownersUnverified, fmt.Sprintf("team members could not be looked up for %s", strings.ReplaceAll(ownersErr.Error(), "\n", "; ")))
+	}
+	if len(ownersUnverified) > 0 {
+		exceptions = append(exceptions, Exception{
+			Type:   ExceptionOwnersUnverified,
+			Detail: strings.Join(ownersUnverified, "; "),
+		})
+	}
+
+	// Parts of the PR the provider failed to fetch need to be reviewed by hand
+	if len(pr.Unverified) > 0 {
+		exceptions = append(exceptions, Exception{
+			Type:   ExceptionNotVerified,
+			Detail: fmt.Sprintf("could not fetch the %s", strings.Join(pr.Unverified, "; ")),
+		})
+	}
+
+	// Branches that require signed commits must only receive verified commits
+	if unsigned := unsignedCommits(pr); len(unsigned) > 0 && requiresSignedCommits(pr) {
+		exceptions = append(exceptions, Exception{
+			Type:   ExceptionUnsignedCommits,
+			Detail: fmt.Sprintf("%s without a verified signature: %s", plural(len(unsigned), "commit"), strings.Join(unsigned, ", ")),
+		})
+	}
+
+	// People checks apply only when an identity mapping is loaded
+	if identities != nil {
+		for _, participant := range prParticipants(pr) {
+			login, role := participant[0], participant[1]
+			if identityCategory(login) == identityUnmapped {
+				exceptions = append(exceptions, Exception{
+					Type:   ExceptionUnmappedIdentity,
+					Detail: fmt.Sprintf("%s %s is not in the identity mapping", role, login),
+				})
+			}
+			if identity, ended := employmentEndedBefore(login, *pr.MergedAt); ended {
+				exceptions = append(exceptions, Exception{
+					Type:   ExceptionFormerEmployee,
+					Detail: fmt.Sprintf("%s %s left on %s, before the merge on %s", role, personName(login), identity.EndDate, pr.MergedAt.Format("2006-01-02")),
+				})
+			}
+		}
+	}
+
+	return exceptions
+}
+
+// evaluateRetrospective checks that a change merged under a retrospective window was approved by a
+// human or linked to a retrospective ticket within that window
+func evaluateRetrospective(pr PullRequest, category *changeCategory) *Exception {
+	deadline := pr.MergedAt.Add(category.window)
+	for _, login := range approversBefore(pr, &deadline) {
+		if !isBotLogin(login) {
+			return nil
+		}
+	}
+	if retrospectiveTicket(pr, category) != "" {
+		return nil
+	}
+
+	detail := fmt.Sprintf("%s change merged by %s without a human approval within %s of the merge", category.Name, mergedByLogin(pr), category.RetrospectiveWindow)
+	if category.ticket != nil {
+		detail = fmt.Sprintf("%s or a retrospective ticket matching %s", detail, category.TicketPattern)
+	}
+	return &Exception{
+		Type:   ExceptionNoRetrospective,
+		Detail: detail,
+	}
+}
+
+// approvers returns the logins that approved the pull request before it was merged, excluding its author
+func approvers(pr PullRequest) []string {
+	return approversBefore(pr, pr.MergedAt)
+}
+
+// approversBefore returns the logins other than the author that approved the pull request no later than cutoff
+func approversBefore(pr PullRequest, cutoff *time.Time) []string {
+	seen := make(map[string]bool)
+	var logins []string
+	for _, review := range pr.Reviews {
+		if review.State != "APPROVED" || review.Author.Login == "" || review.Author.Login == pr.Author.Login {
+			continue
+		}
+		if cutoff != nil && review.SubmittedAt.After(*cutoff) {
+			continue
+		}
+		if !seen[review.Author.Login] {
+			seen[review.Author.Login] = true
+			logins = append(logins, review.Author.Login)
+		}
+	}
+	return logins
+}
+
+// mergedByLogin returns the login of the user who merged the pull request
+func mergedByLogin(pr PullRequest) string {
+	if pr.MergedBy == nil || pr.MergedBy.Login == "" {
+		return "Unknown"
+	}
+	return pr.MergedBy.Login
+}
+
+// exceptionTypes returns the exception types of a pull request joined for display
+func exceptionTypes(pr PullRequest) string {
+	var types []string
+	for _, exception := range pr.Exceptions {
+		types = append(types, exception.Type)
+	}
+	return strings.Join(types, ", ")
+}
@@ -0,0 +1,181 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"sync"
+	"time"
+)
+
+// defaultProductionEnvironment is the deployment environment treated as production
+const defaultProductionEnvironment = "production"
+
+var (
+	trackDeployments bool
+
+	// productionEnvironment is the configured production deployment environment
+	productionEnvironment = defaultProductionEnvironment
+
+	// deploymentRepos holds the repositories, by RepositoryKey, that had deployments or releases to correlate with
+	deploymentRepos = make(map[string]bool)
+)
+
+// Release is a published release or tag of a repository
+type Release struct {
+	Tag         string
+	PublishedAt time.Time
+}
+
+// Deployment is a successful deployment of a commit to an environment
+type Deployment struct {
+	SHA        string
+	DeployedAt time.Time
+}
+
+// deploymentTracker is implemented by providers that can list releases and deployments and
+// tell whether a release or deployed commit includes a change
+type deploymentTracker interface {
+	Releases(repository Repository, since time.Time) ([]Release, error)
+	Deployments(repository Repository, environment string, since time.Time) ([]Deployment, error)
+	ContainsCommit(repository Repository, ref, sha string) (bool, error)
+}
+
+// correlateDeployments records the first release and the first production deployment that
+// include each merged PR's merge commit
+func correlateDeployments(config *RepositoriesConfig, records []PRRecord) {
+	providers := NewProviders()
+	unsupported := make(map[string]bool)
+
+	// Merged PRs by repository, with the earliest merge to bound the releases and deployments fetched
+	repoIndexes := make(map[string][]int)
+	repositories := make(map[string]Repository)
+	since := make(map[string]time.Time)
+	for i, item := range records {
+		if prState(item.PR) != stateMerged || item.PR.MergeCommit == nil || item.PR.MergeCommit.Oid == "" {
+			continue
+		}
+		repository := recordRepository(config, item)
+		if _, ok := providers[repository.Provider].(deploymentTracker); !ok {
+			if !unsupported[repository.Provider] {
+				unsupported[repository.Provider] = true
+				log.Printf("⚠️ Deployment tracking is not supported for %s repositories", repository.Provider)
+			}
+			continue
+		}
+		key := item.RepositoryKey()
+		repoIndexes[key] = append(repoIndexes[key], i)
+		repositories[key] = repository
+		if first, ok := since[key]; !ok || item.PR.MergedAt.Before(first) {
+			since[key] = *item.PR.MergedAt
+		}
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	var deployed, released int
+	semaphore := make(chan struct{}, max(maxWorkers, 1))
+	for repo, indexes := range repoIndexes {
+		repo, indexes := repo, indexes
+		wg.Add(1)
+		semaphore <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-semaphore }()
+
+			repository := repositories[repo]
+			tracker := providers[repository.Provider].(deploymentTracker)
+			releases, err := tracker.Releases(repository, since[repo])
+			if err != nil {
+				log.Printf("⚠️ Could not list releases of %s, skipping it: %v", repo, err)
+				return
+			}
+			deployments, err := tracker.Deployments(repository, productionEnvironment, since[repo])
+			if err != nil {
+				log.Printf("⚠️ Could not list %s deployments of %s, skipping it: %v", productionEnvironment, repo, err)
+				return
+			}
+			if len(releases) == 0 && len(deployments) == 0 {
+				return
+			}
+			sort.SliceStable(releases, func(i, j int) bool { return releases[i].PublishedAt.Before(releases[j].PublishedAt) })
+			sort.SliceStable(deployments, func(i, j int) bool { return deployments[i].DeployedAt.Before(deployments[j].DeployedAt) })
+
+			includes := func(ref, sha string) bool {
+				result, err := tracker.ContainsCommit(repository, ref, sha)
+				if err != nil {
+					log.Printf("⚠️ Could not compare %s with %s in %s: %v", shortSHA(sha), ref, repo, err)
+				}
+				return result
+			}
+
+			// Releases and production deployments move forward: once one includes a change, the later
+			// ones do too. The first to include each PR is found by binary search among those after its
+			// merge, so a PR costs a few comparisons however many releases and deployments there are.
+			var repoDeployed, repoReleased int
+			for _, i := range indexes {
+				pr := &records[i].PR
+				sha := pr.MergeCommit.Oid
+
+				after := sort.Search(len(releases), func(k int) bool { return !releases[k].PublishedAt.Before(*pr.MergedAt) })
+				candidates := releases[after:]
+				if k := sort.Search(len(candidates), func(k int) bool { return includes(candidates[k].Tag, sha) }); k < len(candidates) {
+					publishedAt := candidates[k].PublishedAt
+					pr.ReleasedIn, pr.ReleasedAt = candidates[k].Tag, &publishedAt
+					repoReleased++
+				}
+
+				after = sort.Search(len(deployments), func(k int) bool { return !deployments[k].DeployedAt.Before(*pr.MergedAt) })
+				deployed := deployments[after:]
+				if k := sort.Search(len(deployed), func(k int) bool { return includes(deployed[k].SHA, sha) }); k < len(deployed) {
+					deployedAt := deployed[k].DeployedAt
+					pr.DeployedAt = &deployedAt
+					repoDeployed++
+				}
+			}
+
+			mu.Lock()
+			deploymentRepos[repo] = true
+			deployed += repoDeployed
+			released += repoReleased
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+
+	fmt.Printf("🚀 Deployments: %d PRs deployed to %s, %d released, in %d repositories with deployments or releases\n",
+		deployed, productionEnvironment, released, len(deploymentRepos))
+}
+
+// reachedProduction reports whether a merged PR was deployed to production or released
+func reachedProduction(pr PullRequest) bool {
+	return pr.DeployedAt != nil || pr.ReleasedIn != ""
+}
+
+// neverDeployedRecords returns the merged PRs of repositories with deployments or releases
+// that were neither deployed to production nor released
+func neverDeployedRecords(merged []PRRecord) []PRRecord {
+	var never []PRRecord
+	for _, item := range merged {
+		if deploymentRepos[item.RepositoryKey()] && !reachedProduction(item.PR) {
+			never = append(never, item)
+		}
+	}
+	return never
+}
+
+// generateNeverDeployedSection lists the merged PRs that did not reach production
+func generateNeverDeployedSection(output *os.File, never []PRRecord) {
+	fmt.Fprintf(output, "## 🚀 Merged but Never Deployed\n\n")
+	fmt.Fprintf(output, "- **Pull Requests:** %d\n", len(never))
+	fmt.Fprintf(output, "- **Production Environment:** %s\n\n", productionEnvironment)
+
+	fmt.Fprintf(output, "| PR | Title | Author | Merged |\n")
+	fmt.Fprintf(output, "|---|---|---|---|\n")
+	for _, item := range never {
+		fmt.Fprintf(output, "| %s | %s | %s | %s |\n", markdownLink(fmt.Sprintf("%s#%d", item.Repository, item.PR.Number), item.URL()),
+			escapeTableCell(item.PR.Title), personName(item.PR.Author.Login), item.PR.MergedAt.Format("2006-01-02"))
+	}
+	fmt.Fprintf(output, "\n---\n\n")
+}
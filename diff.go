@@ -0,0 +1,436 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/tealeg/xlsx/v3"
+)
+
+var diffOutput string
+
+// Kinds of change between two report runs
+const (
+	diffNew         = "New"
+	diffDisappeared = "Disappeared"
+	diffChanged     = "Evidence Changed"
+)
+
+// diffEntry is a pull request that differs between the previous and the current run
+type diffEntry struct {
+	Change  string
+	Record  PRRecord // Current record, or the previous one for disappeared PRs
+	Details []string // Changed evidence fields as "Field: old → new"
+}
+
+// diffReport is the comparison of two report runs
+type diffReport struct {
+	Previous, Current   *ReportData
+	PreviousFile        string
+	CurrentFile         string
+	Entries             []diffEntry
+	Unchanged           int
+	RemovedRepositories []string // Repositories with PRs in the previous run only
+	AddedRepositories   []string // Repositories with PRs in the current run only
+	WindowsDiffer       bool     // The runs cover different merge date windows
+	WindowStart         string   // Start of the compared window when the runs differ, empty when unbounded
+	WindowEnd           string   // End of the compared window when the runs differ, empty when unbounded
+	OutsideWindow       int      // PRs left out because they were not merged in the compared window
+}
+
+func newDiffCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "diff <previous.json> <current.json>",
+		Short: "Compare two report runs",
+		Long:  "Compares the JSON outputs of two runs and reports new pull requests, pull requests that disappeared and pull requests whose evidence changed, grouped by vertical and repository",
+		Args:  cobra.ExactArgs(2),
+		Run:   runDiff,
+	}
+
+	cmd.Flags().StringVarP(&diffOutput, "output", "o", "pr-diff.md", "Output markdown file (an .xlsx copy is written alongside)")
+
+	return cmd
+}
+
+func runDiff(cmd *cobra.Command, args []string) {
+	loadBotPatterns()
+
+	previous, err := loadReportData(args[0])
+	if err != nil {
+		log.Fatalf("Failed to load previous run: %v", err)
+	}
+	current, err := loadReportData(args[1])
+	if err != nil {
+		log.Fatalf("Failed to load current run: %v", err)
+	}
+
+	report := compareReports(previous, current)
+	report.PreviousFile, report.CurrentFile = args[0], args[1]
+
+	fmt.Printf("🔍 %d new, %d disappeared, %d with changed evidence, %d unchanged\n",
+		report.count(diffNew), report.count(diffDisappeared), report.count(diffChanged), report.Unchanged)
+
+	if err := writeDiffMarkdown(diffOutput, report); err != nil {
+		log.Fatalf("Failed to write diff report: %v", err)
+	}
+	fmt.Printf("📝 Diff report generated: %s\n", diffOutput)
+	if err := writeDiffXLSX(strings.TrimSuffix(diffOutput, ".md")+".xlsx", report); err != nil {
+		log.Fatalf("Failed to write diff workbook: %v", err)
+	}
+}
+
+// compareReports matches pull requests by host, repository and number and classifies the differences.
+// When the runs cover different merge date windows only the PRs merged in their overlap are compared.
+func compareReports(previous, current *ReportData) diffReport {
+	report := diffReport{Previous: previous, Current: current}
+
+	report.WindowsDiffer = previous.StartDate != current.StartDate || previous.EndDate != current.EndDate
+	inWindow := func(PRRecord) bool { return true }
+	if report.WindowsDiffer {
+		report.WindowStart = laterDate(previous.StartDate, current.StartDate)
+		report.WindowEnd = earlierDate(previous.EndDate, current.EndDate)
+		start, end := parseWindowDate(report.WindowStart), parseWindowDate(report.WindowEnd)
+		inWindow = func(item PRRecord) bool {
+			at := item.PR.MergedAt
+			return at != nil && (start == nil || !at.Before(*start)) && (end == nil || !at.After(*end))
+		}
+		log.Printf("⚠️ The runs cover different merge windows (%s to %s and %s to %s); only PRs merged from %s to %s are compared",
+			diffValue(previous.StartDate), diffValue(previous.EndDate), diffValue(current.StartDate), diffValue(current.EndDate),
+			diffValue(report.WindowStart), diffValue(report.WindowEnd))
+	}
+
+	previousRecords := make(map[string]PRRecord)
+	previousRepos := make(map[string]bool)
+	for _, item := range previous.PullRequests {
+		if !inWindow(item) {
+			report.OutsideWindow++
+			continue
+		}
+		previousRecords[diffKey(item)] = item
+		previousRepos[item.RepositoryKey()] = true
+	}
+
+	currentKeys := make(map[string]bool)
+	currentRepos := make(map[string]bool)
+	for _, item := range current.PullRequests {
+		if !inWindow(item) {
+			report.OutsideWindow++
+			continue
+		}
+		key := diffKey(item)
+		currentKeys[key] = true
+		currentRepos[item.RepositoryKey()] = true
+
+		old, ok := previousRecords[key]
+		if !ok {
+			report.Entries = append(report.Entries, diffEntry{Change: diffNew, Record: item})
+			continue
+		}
+		if details := evidenceChanges(old, item); len(details) > 0 {
+			report.Entries = append(report.Entries, diffEntry{Change: diffChanged, Record: item, Details: details})
+		} else {
+			report.Unchanged++
+		}
+	}
+
+	for _, item := range previous.PullRequests {
+		if inWindow(item) && !currentKeys[diffKey(item)] {
+			report.Entries = append(report.Entries, diffEntry{Change: diffDisappeared, Record: item})
+		}
+	}
+
+	for _, repo := range sortedKeys(previousRepos) {
+		if !currentRepos[repo] {
+			report.RemovedRepositories = append(report.RemovedRepositories, repo)
+		}
+	}
+	for _, repo := range sortedKeys(currentRepos) {
+		if !previousRepos[repo] {
+			report.AddedRepositories = append(report.AddedRepositories, repo)
+		}
+	}
+
+	sort.SliceStable(report.Entries, func(i, j int) bool {
+		a, b := report.Entries[i].Record, report.Entries[j].Record
+		if a.RepositoryKey() != b.RepositoryKey() {
+			return a.RepositoryKey() < b.RepositoryKey()
+		}
+		return a.PR.Number > b.PR.Number
+	})
+
+	return report
+}
+
+// laterDate returns the later of two YYYY-MM-DD dates, where empty means unbounded
+func laterDate(a, b string) string {
+	if a == "" || b > a {
+		return b
+	}
+	return a
+}
+
+// earlierDate returns the earlier of two YYYY-MM-DD dates, where empty means unbounded
+func earlierDate(a, b string) string {
+	if a == "" || (b != "" && b < a) {
+		return b
+	}
+	return a
+}
+
+// parseWindowDate parses a run's YYYY-MM-DD window date, or returns nil when it is unbounded
+func parseWindowDate(date string) *time.Time {
+	if date == "" {
+		return nil
+	}
+	parsed, err := time.Parse("2006-01-02", date)
+	if err != nil {
+		log.Fatalf("Invalid date %q in report: %v", date, err)
+	}
+	return &parsed
+}
+
+// windowDescription describes the compared window when the runs cover different windows
+func (r diffReport) windowDescription() string {
+	return fmt.Sprintf("merged %s to %s (%d PRs outside it not compared)", diffValue(r.WindowStart), diffValue(r.WindowEnd), r.OutsideWindow)
+}
+
+// diffKey identifies a pull request across runs
+func diffKey(item PRRecord) string {
+	return fmt.Sprintf("%s/%s#%d", normalizeHost(item.Host), item.Repository, item.PR.Number)
+}
+
+// evidenceFields returns the audit evidence of a pull request as ordered name/value pairs
+func evidenceFields(item PRRecord) [][2]string {
+	pr := item.PR
+
+	mergedAt := ""
+	if pr.MergedAt != nil {
+		mergedAt = pr.MergedAt.UTC().Format(time.RFC3339)
+	}
+
+	var reviews []string
+	for _, review := range pr.Reviews {
+		reviews = append(reviews, fmt.Sprintf("%s:%s", review.Author.Login, review.State))
+	}
+	sort.Strings(reviews)
+
+	return [][2]string{
+		{"Title", pr.Title},
+		{"State", pr.State},
+		{"Author", pr.Author.Login},
+		{"Author Type", authorType(pr)},
+		{"Merged At", mergedAt},
+		{"Merged By", mergedByLogin(pr)},
+		{"Approvers", strings.Join(approvers(pr), ", ")},
+		{"Reviews", strings.Join(reviews, ", ")},
+		{"Exceptions", exceptionTypes(pr)},
+	}
+}
+
+// evidenceChanges describes every evidence field that differs between two runs of the same pull request
+func evidenceChanges(previous, current PRRecord) []string {
+	before, after := evidenceFields(previous), evidenceFields(current)
+	var details []string
+	for i := range before {
+		if before[i][1] != after[i][1] {
+			details = append(details, fmt.Sprintf("%s: %s → %s", before[i][0], diffValue(before[i][1]), diffValue(after[i][1])))
+		}
+	}
+	return details
+}
+
+// diffValue shows empty values explicitly
+func diffValue(value string) string {
+	if value == "" {
+		return "(none)"
+	}
+	return value
+}
+
+// count returns the number of entries of a kind of change
+func (r diffReport) count(change string) int {
+	n := 0
+	for _, entry := range r.Entries {
+		if entry.Change == change {
+			n++
+		}
+	}
+	return n
+}
+
+// runDescription summarizes a run's generation time and date window
+func runDescription(filename string, data *ReportData) string {
+	description := filename
+	if !data.Generated.IsZero() {
+		description += fmt.Sprintf(", generated %s", data.Generated.Format("2006-01-02 15:04:05 MST"))
+	}
+	if data.StartDate != "" || data.EndDate != "" {
+		description += fmt.Sprintf(", merged %s to %s", diffValue(data.StartDate), diffValue(data.EndDate))
+	}
+	return fmt.Sprintf("%s (%d PRs)", description, len(data.PullRequests))
+}
+
+// writeDiffMarkdown writes the delta report grouped by vertical (when configured) and repository
+func writeDiffMarkdown(filename string, report diffReport) error {
+	output, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	defer output.Close()
+
+	fmt.Fprintf(output, "# Pull Request Report Diff\n\n")
+	fmt.Fprintf(output, "**Generated:** %s\n\n", time.Now().Format("2006-01-02 15:04:05 MST"))
+	fmt.Fprintf(output, "- **Previous run:** %s\n", runDescription(report.PreviousFile, report.Previous))
+	fmt.Fprintf(output, "- **Current run:** %s\n", runDescription(report.CurrentFile, report.Current))
+	if report.WindowsDiffer {
+		fmt.Fprintf(output, "- **Compared window:** %s\n\n", report.windowDescription())
+		fmt.Fprintf(output, "> ⚠️ The runs cover different merge windows, so only PRs merged in both are compared.\n")
+	}
+	fmt.Fprintf(output, "\n")
+
+	fmt.Fprintf(output, "## Summary\n\n")
+	fmt.Fprintf(output, "- **New PRs:** %d\n", report.count(diffNew))
+	fmt.Fprintf(output, "- **Disappeared PRs:** %d\n", report.count(diffDisappeared))
+	fmt.Fprintf(output, "- **PRs with Changed Evidence:** %d\n", report.count(diffChanged))
+	fmt.Fprintf(output, "- **Unchanged PRs:** %d\n\n", report.Unchanged)
+
+	if len(report.RemovedRepositories) > 0 {
+		fmt.Fprintf(output, "**Repositories no longer reported** (deleted, renamed or access lost?): %s\n\n", strings.Join(report.RemovedRepositories, ", "))
+	}
+	if len(report.AddedRepositories) > 0 {
+		fmt.Fprintf(output, "**Repositories newly reported:** %s\n\n", strings.Join(report.AddedRepositories, ", "))
+	}
+
+	var records []PRRecord
+	for _, entry := range report.Entries {
+		records = append(records, entry.Record)
+	}
+
+	if !hasVerticals(records) {
+		fmt.Fprintf(output, "---\n\n")
+		writeDiffRepositorySections(output, "##", report.Entries)
+		return nil
+	}
+
+	// Per-vertical counts, then one section per vertical
+	byVertical := make(map[string][]diffEntry)
+	for _, entry := range report.Entries {
+		for _, vertical := range recordVerticals(entry.Record) {
+			byVertical[vertical] = append(byVertical[vertical], entry)
+		}
+	}
+
+	fmt.Fprintf(output, "| Vertical | New | Disappeared | Evidence Changed |\n")
+	fmt.Fprintf(output, "|---|---:|---:|---:|\n")
+	for _, vertical := range sortedKeys(byVertical) {
+		counts := diffReport{Entries: byVertical[vertical]}
+		fmt.Fprintf(output, "| %s | %d | %d | %d |\n", vertical, counts.count(diffNew), counts.count(diffDisappeared), counts.count(diffChanged))
+	}
+	fmt.Fprintf(output, "\n---\n\n")
+
+	for _, vertical := range sortedKeys(byVertical) {
+		fmt.Fprintf(output, "## Vertical: %s\n\n", vertical)
+		writeDiffRepositorySections(output, "###", byVertical[vertical])
+	}
+	return nil
+}
+
+// writeDiffRepositorySections writes the changes of each repository
+func writeDiffRepositorySections(output *os.File, heading string, entries []diffEntry) {
+	byRepo := make(map[string][]diffEntry)
+	for _, entry := range entries {
+		byRepo[entry.Record.RepositoryKey()] = append(byRepo[entry.Record.RepositoryKey()], entry)
+	}
+
+	for _, repo := range sortedKeys(byRepo) {
+		fmt.Fprintf(output, "%s %s\n\n", heading, repo)
+		for _, entry := range byRepo[repo] {
+			pr := entry.Record.PR
+			icon := map[string]string{diffNew: "🆕", diffDisappeared: "➖", diffChanged: "✏️"}[entry.Change]
+
+			fmt.Fprintf(output, "- %s **%s** %s %s", icon, entry.Change, markdownLink(fmt.Sprintf("#%d", pr.Number), entry.Record.URL()), pr.Title)
+			if pr.Author.Login != "" {
+				fmt.Fprintf(output, " by **%s**", pr.Author.Login)
+			}
+			if pr.MergedAt != nil {
+				fmt.Fprintf(output, " - merged %s", pr.MergedAt.Format("2006-01-02"))
+			}
+			fmt.Fprintf(output, "\n")
+
+			for _, detail := range entry.Details {
+				fmt.Fprintf(output, "  - %s\n", detail)
+			}
+		}
+		fmt.Fprintf(output, "\n")
+	}
+}
+
+// writeDiffXLSX writes the summary and a filterable table of every change
+func writeDiffXLSX(filename string, report diffReport) error {
+	file := xlsx.NewFile()
+	bold := xlsxHeaderStyle()
+
+	summary, err := file.AddSheet("Summary")
+	if err != nil {
+		return err
+	}
+	addRow := func(label, value string) {
+		row := summary.AddRow()
+		row.AddCell().SetString(label)
+		row.AddCell().SetString(value)
+	}
+	addRow("Previous Run", runDescription(report.PreviousFile, report.Previous))
+	addRow("Current Run", runDescription(report.CurrentFile, report.Current))
+	if report.WindowsDiffer {
+		addRow("Compared Window", report.windowDescription())
+	}
+	addRow("New PRs", fmt.Sprintf("%d", report.count(diffNew)))
+	addRow("Disappeared PRs", fmt.Sprintf("%d", report.count(diffDisappeared)))
+	addRow("PRs with Changed Evidence", fmt.Sprintf("%d", report.count(diffChanged)))
+	addRow("Unchanged PRs", fmt.Sprintf("%d", report.Unchanged))
+	addRow("Repositories No Longer Reported", strings.Join(report.RemovedRepositories, ", "))
+	addRow("Repositories Newly Reported", strings.Join(report.AddedRepositories, ", "))
+	summary.SetColWidth(1, 1, 32)
+	summary.SetColWidth(2, 2, 80)
+
+	sheet, err := file.AddSheet("Changes")
+	if err != nil {
+		return err
+	}
+	headers := []string{"Change", "Repository", "Verticals", "PR_Number", "Title", "Author", "Merge_Date", "Details"}
+	addHeaderRow(sheet, bold, headers...)
+	for _, entry := range report.Entries {
+		pr := entry.Record.PR
+		row := sheet.AddRow()
+		row.AddCell().SetString(entry.Change)
+		row.AddCell().SetString(entry.Record.RepositoryKey())
+		row.AddCell().SetString(strings.Join(entry.Record.Verticals, ", "))
+		setLinkCell(row.AddCell(), entry.Record.URL(), fmt.Sprintf("#%d", pr.Number))
+		row.AddCell().SetString(pr.Title)
+		row.AddCell().SetString(pr.Author.Login)
+		cell := row.AddCell()
+		if pr.MergedAt != nil {
+			cell.SetDateWithOptions(*pr.MergedAt, xlsxDateOptions)
+		}
+		row.AddCell().SetString(strings.Join(entry.Details, "\n"))
+	}
+	for i, width := range []float64{18, 30, 20, 12, 50, 20, 14, 60} {
+		sheet.SetColWidth(i+1, i+1, width)
+	}
+	freezeHeaderRow(sheet)
+	sheet.AutoFilter = &xlsx.AutoFilter{
+		TopLeftCell:     "A1",
+		BottomRightCell: xlsx.GetCellIDStringFromCoords(len(headers)-1, len(report.Entries)),
+	}
+
+	if err := file.Save(filename); err != nil {
+		return err
+	}
+	fmt.Printf("📊 Diff workbook generated: %s\n", filename)
+	return nil
+}
@@ -0,0 +1,612 @@
+package main
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	evidenceFromSample string
+	evidenceOutputDir  string
+)
+
+// prReferencePattern matches "owner/repo#123", "owner/repo/pull/123" and PR URLs
+var prReferencePattern = regexp.MustCompile(`^(?:https?://([^/]+)/)?([^/\s#]+)/([^/\s#]+)(?:#|/pull/|/pulls/)(\d+)/?$`)
+
+// PRReference identifies a single pull request
+type PRReference struct {
+	Host   string
+	Owner  string
+	Name   string
+	Number int
+}
+
+// EvidenceManifest describes the contents of an evidence package
+type EvidenceManifest struct {
+	Generated    time.Time              `json:"generated"`
+	PullRequests []string               `json:"pullRequests"`
+	Files        []EvidenceManifestFile `json:"files"`
+}
+
+// EvidenceManifestFile describes a single file in an evidence package
+type EvidenceManifestFile struct {
+	Path   string `json:"path"`
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256"`
+}
+
+// reviewComment is the subset of an inline review comment shown in evidence summaries
+type reviewComment struct {
+	User      Actor  `json:"user"`
+	Path      string `json:"path"`
+	Line      *int   `json:"line"`
+	Body      string `json:"body"`
+	CreatedAt string `json:"created_at"`
+}
+
+// timelineEvent is the subset of a timeline event shown in evidence summaries
+type timelineEvent struct {
+	Event       string `json:"event"`
+	Actor       *Actor `json:"actor"`
+	User        *Actor `json:"user"`
+	CreatedAt   string `json:"created_at"`
+	SubmittedAt string `json:"submitted_at"`
+	State       string `json:"state"`
+	Sha         string `json:"sha"`
+}
+
+func newEvidenceCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "evidence [owner/repo#number | PR URL]...",
+		Short: "Export evidence packages for selected pull requests",
+		Long:  "Fetches the full record of each pull request (description, reviews, comments, commits, checks, timeline and changed files) and writes one folder per PR plus a zip archive with a manifest",
+		Run:   runEvidence,
+	}
+
+	cmd.Flags().StringVar(&evidenceFromSample, "from-sample", "", "Read the pull requests to export from a sample JSON file")
+	cmd.Flags().StringVarP(&evidenceOutputDir, "output", "o", "pr-evidence", "Output directory for evidence packages (a .zip is written alongside)")
+
+	return cmd
+}
+
+func runEvidence(cmd *cobra.Command, args []string) {
+	var refs []PRReference
+	for _, arg := range args {
+		ref, err := parsePRReference(arg)
+		if err != nil {
+			log.Fatalf("Invalid pull request reference: %v", err)
+		}
+		refs = append(refs, ref)
+	}
+
+	loadIdentityMapping()
+	loadBotPatterns()
+
+	if evidenceFromSample != "" {
+		sampleRefs, err := loadSampleReferences(evidenceFromSample)
+		if err != nil {
+			log.Fatalf("Failed to load sample: %v", err)
+		}
+		refs = append(refs, sampleRefs...)
+	}
+
+	if len(refs) == 0 {
+		log.Fatalf("No pull requests given (pass references as arguments or use --from-sample)")
+	}
+
+	githubClient := NewGitHubClient()
+	available := checkEvidenceHosts(githubClient, refs)
+
+	if err := os.MkdirAll(evidenceOutputDir, 0755); err != nil {
+		log.Fatalf("Failed to create output directory: %v", err)
+	}
+
+	var exported, folders []string
+	for _, ref := range refs {
+		if !available[ref.Host] {
+			continue
+		}
+
+		evidence, err := githubClient.FetchPullRequestEvidence(ref.Host, ref.Owner, ref.Name, ref.Number)
+		if err != nil {
+			log.Printf("❌ Warning: %v", err)
+			continue
+		}
+
+		dir := filepath.Join(evidenceOutputDir, ref.folderName())
+		if err := writeEvidenceFolder(dir, evidence); err != nil {
+			log.Printf("❌ Warning: Failed to write evidence for %s: %v", ref, err)
+			continue
+		}
+
+		fmt.Printf("✅ %s: evidence written to %s\n", ref, dir)
+		exported = append(exported, ref.String())
+		folders = append(folders, dir)
+	}
+
+	zipFile := strings.TrimSuffix(evidenceOutputDir, string(filepath.Separator)) + ".zip"
+	if err := writeEvidenceZip(evidenceOutputDir, zipFile, exported, folders); err != nil {
+		log.Fatalf("Failed to write evidence archive: %v", err)
+	}
+
+	fmt.Printf("\n📦 Evidence package generated: %s (%d of %d pull requests)\n", zipFile, len(exported), len(refs))
+}
+
+// String formats the reference as owner/repo#number, prefixed with the host outside github.com
+func (r PRReference) String() string {
+	if r.Host != defaultHost {
+		return fmt.Sprintf("%s/%s/%s#%d", r.Host, r.Owner, r.Name, r.Number)
+	}
+	return fmt.Sprintf("%s/%s#%d", r.Owner, r.Name, r.Number)
+}
+
+// folderName returns the evidence folder of the pull request, host-owner-repo-number, so that
+// same-named repositories on different hosts never share a folder
+func (r PRReference) folderName() string {
+	return fmt.Sprintf("%s-%s-%s-%d", r.Host, r.Owner, r.Name, r.Number)
+}
+
+// checkEvidenceHosts checks gh is authenticated to the host of every reference and returns the
+// hosts evidence can be fetched from; PRs on the other hosts are skipped with a warning
+func checkEvidenceHosts(githubClient *GitHubClient, refs []PRReference) map[string]bool {
+	available := make(map[string]bool)
+	checked := make(map[string]bool)
+	for _, ref := range refs {
+		if checked[ref.Host] {
+			continue
+		}
+		checked[ref.Host] = true
+		if err := githubClient.CheckGitHubCLI([]string{ref.Host}); err != nil {
+			log.Printf("⚠️ Skipping the pull requests on %s: %v", ref.Host, err)
+			continue
+		}
+		available[ref.Host] = true
+	}
+	if len(available) == 0 {
+		log.Fatalf("GitHub CLI check failed for every host; no evidence can be exported")
+	}
+	return available
+}
+
+// parsePRReference parses "owner/repo#123", "owner/repo/pull/123" or a PR URL
+func parsePRReference(s string) (PRReference, error) {
+	matches := prReferencePattern.FindStringSubmatch(strings.TrimSpace(s))
+	if matches == nil {
+		return PRReference{}, fmt.Errorf("%q (expected owner/repo#number or a pull request URL)", s)
+	}
+
+	number, err := strconv.Atoi(matches[4])
+	if err != nil {
+		return PRReference{}, fmt.Errorf("%q: %w", s, err)
+	}
+
+	return PRReference{Host: normalizeHost(matches[1]), Owner: matches[2], Name: matches[3], Number: number}, nil
+}
+
+// loadSampleReferences reads the pull requests selected by the sample subcommand
+func loadSampleReferences(filename string) ([]PRReference, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	var sample SampleResult
+	if err := json.Unmarshal(data, &sample); err != nil {
+		return nil, fmt.Errorf("failed to parse sample file %s: %w", filename, err)
+	}
+
+	var refs []PRReference
+	for _, item := range sample.Items {
+		// Evidence is fetched with gh, so only GitHub pull requests can be exported
+		if item.Provider != "" && item.Provider != providerGitHub {
+			log.Printf("⚠️ Skipping %s#%d: evidence can only be exported for GitHub pull requests, not %s", item.RepositoryKey(), item.PR.Number, item.Provider)
+			continue
+		}
+		ref, err := parsePRReference(fmt.Sprintf("%s#%d", item.Repository, item.PR.Number))
+		if err != nil {
+			return nil, err
+		}
+		ref.Host = normalizeHost(item.Host)
+		refs = append(refs, ref)
+	}
+
+	return refs, nil
+}
+
+// writeEvidenceFolder writes the raw JSON records and rendered summaries for one pull request
+func writeEvidenceFolder(dir string, evidence *PullRequestEvidence) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	jsonFiles := map[string]json.RawMessage{
+		"pr.json":              evidence.Raw,
+		"review-comments.json": evidence.ReviewComments,
+		"timeline.json":        evidence.Timeline,
+	}
+	for name, data := range jsonFiles {
+		var indented bytes.Buffer
+		if err := json.Indent(&indented, data, "", "  "); err != nil {
+			return fmt.Errorf("invalid JSON for %s: %w", name, err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, name), indented.Bytes(), 0644); err != nil {
+			return err
+		}
+	}
+
+	var comments []reviewComment
+	if err := json.Unmarshal(evidence.ReviewComments, &comments); err != nil {
+		return fmt.Errorf("failed to parse review comments: %w", err)
+	}
+	var events []timelineEvent
+	if err := json.Unmarshal(evidence.Timeline, &events); err != nil {
+		return fmt.Errorf("failed to parse timeline: %w", err)
+	}
+
+	var markdown bytes.Buffer
+	generateEvidenceMarkdown(&markdown, evidence, comments, events)
+	if err := os.WriteFile(filepath.Join(dir, "summary.md"), markdown.Bytes(), 0644); err != nil {
+		return err
+	}
+
+	htmlFile, err := os.Create(filepath.Join(dir, "summary.html"))
+	if err != nil {
+		return err
+	}
+	defer htmlFile.Close()
+
+	approval := approvalOfNewestCommit(evidence.Detail.Author.Login, evidence.Detail.Reviews, evidence.Detail.MergedAt, evidence.Detail.Commits)
+	return evidenceHTMLTemplate.Execute(htmlFile, struct {
+		Repository string
+		PR         PullRequestDetail
+		Comments   []reviewComment
+		Events     []timelineEvent
+		Approval   *Review
+		Unreviewed []PRCommit
+	}{evidence.Repository, evidence.Detail, comments, events, approval, commitsAfterApproval(approval, evidence.Detail.Commits)})
+}
+
+func generateEvidenceMarkdown(output io.Writer, evidence *PullRequestEvidence, comments []reviewComment, events []timelineEvent) {
+	pr := evidence.Detail
+
+	fmt.Fprintf(output, "# %s#%d: %s\n\n", evidence.Repository, pr.Number, pr.Title)
+	fmt.Fprintf(output, "- **Link:** [%s](%s)\n", pr.URL, pr.URL)
+	fmt.Fprintf(output, "- **State:** %s\n", pr.State)
+	fmt.Fprintf(output, "- **Author:** %s\n", personName(pr.Author.Login))
+	fmt.Fprintf(output, "- **Branches:** %s ← %s\n", pr.BaseRefName, pr.HeadRefName)
+	fmt.Fprintf(output, "- **Created:** %s\n", pr.CreatedAt.Format("2006-01-02 15:04:05 MST"))
+	if pr.MergedAt != nil {
+		fmt.Fprintf(output, "- **Merged:** %s\n", pr.MergedAt.Format("2006-01-02 15:04:05 MST"))
+	}
+	if pr.MergedBy != nil {
+		fmt.Fprintf(output, "- **Merged By:** %s\n", personName(pr.MergedBy.Login))
+	}
+	if pr.MergeCommit != nil {
+		fmt.Fprintf(output, "- **Merge Commit:** %s", shortSHA(pr.MergeCommit.Oid))
+		if pr.MergeCommit.Signature != nil {
+			fmt.Fprintf(output, " - %s", signatureStatus(pr.MergeCommit.Signature))
+		}
+		fmt.Fprintf(output, "\n")
+	}
+	if pr.ReviewDecision != "" {
+		fmt.Fprintf(output, "- **Review Decision:** %s\n", pr.ReviewDecision)
+	}
+	fmt.Fprintf(output, "- **Evidence Captured:** %s\n\n", time.Now().Format("2006-01-02 15:04:05 MST"))
+
+	fmt.Fprintf(output, "## Description\n\n")
+	if strings.TrimSpace(pr.Body) == "" {
+		fmt.Fprintf(output, "_No description provided._\n\n")
+	} else {
+		fmt.Fprintf(output, "%s\n\n", strings.TrimSpace(pr.Body))
+	}
+
+	fmt.Fprintf(output, "## Reviews (%d)\n\n", len(pr.Reviews))
+	if len(pr.Reviews) > 0 {
+		fmt.Fprintf(output, "| Reviewer | State | Submitted | Commit |\n|---|---|---|---|\n")
+		for _, review := range pr.Reviews {
+			fmt.Fprintf(output, "| %s | %s | %s | %s |\n", personName(review.Author.Login), review.State,
+				review.SubmittedAt.Format("2006-01-02 15:04:05"), shortSHA(review.Commit.Oid))
+		}
+		fmt.Fprintf(output, "\n")
+	}
+
+	fmt.Fprintf(output, "## Review Comments (%d)\n\n", len(comments))
+	for _, comment := range comments {
+		location := comment.Path
+		if comment.Line != nil {
+			location = fmt.Sprintf("%s:%d", comment.Path, *comment.Line)
+		}
+		fmt.Fprintf(output, "- **%s** on `%s` (%s): %s\n", comment.User.Login, location, comment.CreatedAt, oneLine(comment.Body))
+	}
+	if len(comments) > 0 {
+		fmt.Fprintf(output, "\n")
+	}
+
+	fmt.Fprintf(output, "## Conversation Comments (%d)\n\n", len(pr.Comments))
+	for _, comment := range pr.Comments {
+		fmt.Fprintf(output, "- **%s** (%s): %s\n", comment.Author.Login, comment.CreatedAt.Format("2006-01-02 15:04:05"), oneLine(comment.Body))
+	}
+	if len(pr.Comments) > 0 {
+		fmt.Fprintf(output, "\n")
+	}
+
+	fmt.Fprintf(output, "## Commits (%d)\n\n", len(pr.Commits))
+	if len(pr.Commits) > 0 {
+		fmt.Fprintf(output, "| Commit | Message | Committed | Signature |\n|---|---|---|---|\n")
+		for _, commit := range pr.Commits {
+			fmt.Fprintf(output, "| %s | %s | %s | %s |\n", shortSHA(commit.Oid), escapeTableCell(commit.MessageHeadline),
+				commit.CommittedDate.Format("2006-01-02 15:04:05"), signatureStatus(commit.Signature))
+		}
+		fmt.Fprintf(output, "\n")
+	}
+
+	fmt.Fprintf(output, "## Commits After Approval\n\n")
+	if approval := approvalOfNewestCommit(pr.Author.Login, pr.Reviews, pr.MergedAt, pr.Commits); approval == nil {
+		fmt.Fprintf(output, "_No approval before the merge._\n\n")
+	} else if unreviewed := commitsAfterApproval(approval, pr.Commits); len(unreviewed) == 0 {
+		fmt.Fprintf(output, "_None: the approval by %s on %s covers the final commit._\n\n",
+			personName(approval.Author.Login), approval.SubmittedAt.Format("2006-01-02 15:04:05"))
+	} else {
+		fmt.Fprintf(output, "⚠️ %s added after the approval by %s on %s (approved commit %s):\n\n",
+			plural(len(unreviewed), "commit"), personName(approval.Author.Login), approval.SubmittedAt.Format("2006-01-02 15:04:05"), shortSHA(approval.Commit.Oid))
+		fmt.Fprintf(output, "| Commit | Message | Committed |\n|---|---|---|\n")
+		for _, commit := range unreviewed {
+			fmt.Fprintf(output, "| %s | %s | %s |\n", shortSHA(commit.Oid), escapeTableCell(commit.MessageHeadline),
+				commit.CommittedDate.Format("2006-01-02 15:04:05"))
+		}
+		fmt.Fprintf(output, "\n")
+	}
+
+	fmt.Fprintf(output, "## Checks (%d)\n\n", len(pr.StatusCheckRollup))
+	if len(pr.StatusCheckRollup) > 0 {
+		fmt.Fprintf(output, "| Check | Result |\n|---|---|\n")
+		for _, check := range pr.StatusCheckRollup {
+			fmt.Fprintf(output, "| %s | %s |\n", escapeTableCell(check.DisplayName()), check.Result())
+		}
+		fmt.Fprintf(output, "\n")
+	}
+
+	fmt.Fprintf(output, "## Changed Files (%d)\n\n", len(pr.Files))
+	for _, file := range pr.Files {
+		fmt.Fprintf(output, "- `%s` (+%d/-%d)\n", file.Path, file.Additions, file.Deletions)
+	}
+	if len(pr.Files) > 0 {
+		fmt.Fprintf(output, "\n")
+	}
+
+	fmt.Fprintf(output, "## Timeline (%d events)\n\n", len(events))
+	if len(events) > 0 {
+		fmt.Fprintf(output, "| Time | Event | Actor |\n|---|---|---|\n")
+		for _, event := range events {
+			fmt.Fprintf(output, "| %s | %s | %s |\n", event.Time(), event.Event, event.ActorLogin())
+		}
+	}
+}
+
+// writeEvidenceZip archives the PR folders written in this run together with a manifest of file
+// hashes, leaving out folders of earlier runs in the same directory
+func writeEvidenceZip(dir, zipFile string, pullRequests, folders []string) error {
+	manifest := EvidenceManifest{
+		Generated:    time.Now(),
+		PullRequests: pullRequests,
+	}
+
+	var paths []string
+	walked := make(map[string]bool)
+	for _, folder := range folders {
+		if walked[folder] {
+			continue
+		}
+		walked[folder] = true
+		err := filepath.Walk(folder, func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			if !info.IsDir() {
+				paths = append(paths, path)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+	sort.Strings(paths)
+
+	out, err := os.Create(zipFile)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	archive := zip.NewWriter(out)
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+
+		sum := sha256.Sum256(data)
+		manifest.Files = append(manifest.Files, EvidenceManifestFile{
+			Path:   rel,
+			Size:   int64(len(data)),
+			SHA256: hex.EncodeToString(sum[:]),
+		})
+
+		w, err := archive.Create(rel)
+		if err != nil {
+			return err
+		}
+		if _, err := w.Write(data); err != nil {
+			return err
+		}
+	}
+
+	manifestData, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(dir, "manifest.json"), manifestData, 0644); err != nil {
+		return err
+	}
+	w, err := archive.Create("manifest.json")
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(manifestData); err != nil {
+		return err
+	}
+
+	return archive.Close()
+}
+
+// DisplayName returns the check run name or status context
+func (c StatusCheck) DisplayName() string {
+	if c.Name != "" {
+		return c.Name
+	}
+	return c.Context
+}
+
+// Result returns the conclusion of a check run or the state of a commit status
+func (c StatusCheck) Result() string {
+	if c.Conclusion != "" {
+		return c.Conclusion
+	}
+	if c.State != "" {
+		return c.State
+	}
+	return c.Status
+}
+
+// Time returns when the timeline event happened
+func (e timelineEvent) Time() string {
+	if e.CreatedAt != "" {
+		return e.CreatedAt
+	}
+	return e.SubmittedAt
+}
+
+// ActorLogin returns the login of the user who triggered the timeline event
+func (e timelineEvent) ActorLogin() string {
+	if e.Actor != nil {
+		return e.Actor.Login
+	}
+	if e.User != nil {
+		return e.User.Login
+	}
+	return ""
+}
+
+// shortSHA abbreviates a commit SHA for display
+func shortSHA(sha string) string {
+	if len(sha) > 7 {
+		return sha[:7]
+	}
+	return sha
+}
+
+// oneLine collapses text onto a single line for list output
+func oneLine(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// escapeTableCell makes text safe to place in a markdown table cell
+func escapeTableCell(s string) string {
+	return strings.ReplaceAll(oneLine(s), "|", "\\|")
+}
+
+var evidenceHTMLTemplate = template.Must(template.New("evidence").Funcs(template.FuncMap{
+	"date":      func(t time.Time) string { return t.Format("2006-01-02 15:04:05 MST") },
+	"person":    personName,
+	"signature": signatureStatus,
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.Repository}}#{{.PR.Number}}: {{.PR.Title}}</title>
+<style>
+body { font-family: -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; margin: 2em; color: #1f2328; }
+table { border-collapse: collapse; margin-bottom: 1.5em; }
+th, td { border: 1px solid #d0d7de; padding: 4px 8px; text-align: left; vertical-align: top; }
+th { background: #f6f8fa; }
+pre { white-space: pre-wrap; background: #f6f8fa; padding: 1em; }
+dt { font-weight: bold; float: left; width: 10em; }
+dd { margin-left: 11em; }
+</style>
+</head>
+<body>
+<h1>{{.Repository}}#{{.PR.Number}}: {{.PR.Title}}</h1>
+<dl>
+<dt>Link</dt><dd><a href="{{.PR.URL}}">{{.PR.URL}}</a></dd>
+<dt>State</dt><dd>{{.PR.State}}</dd>
+<dt>Author</dt><dd>{{person .PR.Author.Login}}</dd>
+<dt>Branches</dt><dd>{{.PR.BaseRefName}} ← {{.PR.HeadRefName}}</dd>
+<dt>Created</dt><dd>{{date .PR.CreatedAt}}</dd>
+{{if .PR.MergedAt}}<dt>Merged</dt><dd>{{date .PR.MergedAt}}</dd>{{end}}
+{{if .PR.MergedBy}}<dt>Merged By</dt><dd>{{person .PR.MergedBy.Login}}</dd>{{end}}
+{{if .PR.MergeCommit}}<dt>Merge Commit</dt><dd><code>{{.PR.MergeCommit.Oid}}</code> {{signature .PR.MergeCommit.Signature}}</dd>{{end}}
+{{if .PR.ReviewDecision}}<dt>Review Decision</dt><dd>{{.PR.ReviewDecision}}</dd>{{end}}
+</dl>
+<h2>Description</h2>
+<pre>{{.PR.Body}}</pre>
+<h2>Reviews ({{len .PR.Reviews}})</h2>
+<table><tr><th>Reviewer</th><th>State</th><th>Submitted</th><th>Commit</th></tr>
+{{range .PR.Reviews}}<tr><td>{{person .Author.Login}}</td><td>{{.State}}</td><td>{{date .SubmittedAt}}</td><td><code>{{.Commit.Oid}}</code></td></tr>
+{{end}}</table>
+<h2>Review Comments ({{len .Comments}})</h2>
+<table><tr><th>Reviewer</th><th>File</th><th>Time</th><th>Comment</th></tr>
+{{range .Comments}}<tr><td>{{.User.Login}}</td><td><code>{{.Path}}{{if .Line}}:{{.Line}}{{end}}</code></td><td>{{.CreatedAt}}</td><td>{{.Body}}</td></tr>
+{{end}}</table>
+<h2>Conversation Comments ({{len .PR.Comments}})</h2>
+<table><tr><th>Author</th><th>Time</th><th>Comment</th></tr>
+{{range .PR.Comments}}<tr><td>{{.Author.Login}}</td><td>{{date .CreatedAt}}</td><td>{{.Body}}</td></tr>
+{{end}}</table>
+<h2>Commits ({{len .PR.Commits}})</h2>
+<table><tr><th>Commit</th><th>Message</th><th>Committed</th><th>Signature</th></tr>
+{{range .PR.Commits}}<tr><td><code>{{.Oid}}</code></td><td>{{.MessageHeadline}}</td><td>{{date .CommittedDate}}</td><td>{{signature .Signature}}</td></tr>
+{{end}}</table>
+<h2>Commits After Approval</h2>
+{{if not .Approval}}<p><em>No approval before the merge.</em></p>
+{{else if not .Unreviewed}}<p><em>None: the approval by {{person .Approval.Author.Login}} on {{date .Approval.SubmittedAt}} covers the final commit.</em></p>
+{{else}}<p>{{len .Unreviewed}} commit(s) added after the approval by {{person .Approval.Author.Login}} on {{date .Approval.SubmittedAt}}:</p>
+<table><tr><th>Commit</th><th>Message</th><th>Committed</th></tr>
+{{range .Unreviewed}}<tr><td><code>{{.Oid}}</code></td><td>{{.MessageHeadline}}</td><td>{{date .CommittedDate}}</td></tr>
+{{end}}</table>
+{{end}}<h2>Checks ({{len .PR.StatusCheckRollup}})</h2>
+<table><tr><th>Check</th><th>Result</th></tr>
+{{range .PR.StatusCheckRollup}}<tr><td>{{.DisplayName}}</td><td>{{.Result}}</td></tr>
+{{end}}</table>
+<h2>Changed Files ({{len .PR.Files}})</h2>
+<table><tr><th>File</th><th>Additions</th><th>Deletions</th></tr>
+{{range .PR.Files}}<tr><td><code>{{.Path}}</code></td><td>{{.Additions}}</td><td>{{.Deletions}}</td></tr>
+{{end}}</table>
+<h2>Timeline ({{len .Events}} events)</h2>
+<table><tr><th>Time</th><th>Event</th><th>Actor</th></tr>
+{{range .Events}}<tr><td>{{.Time}}</td><td>{{.Event}}</td><td>{{.ActorLogin}}</td></tr>
+{{end}}</table>
+</body>
+</html>
+`))
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestLoadSampleReferences(t *testing.T) {
+	sample := `{"items": [
+		{"stratum": "All", "repository": "acme/api", "provider": "github", "pr": {"number": 4}},
+		{"stratum": "All", "repository": "acme/api", "host": "git.example.com", "provider": "github", "pr": {"number": 4}},
+		{"stratum": "All", "repository": "acme/api", "pr": {"number": 5}},
+		{"stratum": "All", "repository": "group/sub/api", "host": "gitlab.com", "provider": "gitlab", "pr": {"number": 6}},
+		{"stratum": "All", "repository": "acme/tools", "provider": "local", "pr": {"number": 7}}
+	]}`
+	path := filepath.Join(t.TempDir(), "sample.json")
+	if err := os.WriteFile(path, []byte(sample), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	refs, err := loadSampleReferences(path)
+	if err != nil {
+		t.Fatalf("loadSampleReferences: %v", err)
+	}
+
+	var got, folders []string
+	for _, ref := range refs {
+		got = append(got, ref.String())
+		folders = append(folders, ref.folderName())
+	}
+	want := []string{"acme/api#4", "git.example.com/acme/api#4", "acme/api#5"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("references = %v, want %v", got, want)
+	}
+	wantFolders := []string{"github.com-acme-api-4", "git.example.com-acme-api-4", "github.com-acme-api-5"}
+	if !reflect.DeepEqual(folders, wantFolders) {
+		t.Errorf("folders = %v, want %v", folders, wantFolders)
+	}
+}
@@ -1,14 +1,21 @@
 package main
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
+	"log"
+	"net/url"
+	"os"
 	"os/exec"
 	"strconv"
 	"strings"
-	"sync"
+	"time"
 )
 
+// defaultHost is the GitHub host used for repositories without an explicit host
+const defaultHost = "github.com"
+
 // GitHubClient handles GitHub CLI operations
 type GitHubClient struct{}
 
@@ -18,18 +25,30 @@ func NewGitHubClient() *GitHubClient {
 }
 
 // FetchPullRequests fetches pull requests for a repository using GitHub CLI
-func (gc *GitHubClient) FetchPullRequests(owner, repo string, filter *PRFilter, workerConfig *WorkerConfig) ([]PullRequest, error) {
+func (gc *GitHubClient) FetchPullRequests(repository Repository, filter *PRFilter, workerConfig *WorkerConfig) ([]PullRequest, error) {
+	owner, repo := repository.Owner, repository.Name
+
 	// Build the GitHub CLI command
 	cmd := exec.Command("gh", "pr", "list", 
-		"--repo", fmt.Sprintf("%s/%s", owner, repo),
-		"--state", "merged",
-		"--json", "number,title,state,mergedAt,createdAt,author")
+		"--repo", ghRepoArg(repository.Host, owner, repo),
+		"--state", ghState(filter),
+		"--json", "number,title,state,mergedAt,closedAt,createdAt,author,mergedBy,reviews,url,baseRefName,labels,body,files,mergeCommit,additions,deletions,changedFiles,commits")
 
 	// Set limit if provided
 	if filter != nil && filter.Limit > 0 {
 		cmd.Args = append(cmd.Args, "--limit", strconv.Itoa(filter.Limit))
 	}
 
+	// Narrow the query to a single person in scope; reviewed-by also matches reviews that did not approve
+	if role, login, ok := singleScopedLogin(filter); ok {
+		switch role {
+		case roleAuthored:
+			cmd.Args = append(cmd.Args, "--author", login)
+		case roleApproved:
+			cmd.Args = append(cmd.Args, "--search", "reviewed-by:"+login)
+		}
+	}
+
 	// Execute the command
 	output, err := cmd.Output()
 	if err != nil {
@@ -42,50 +61,51 @@ func (gc *GitHubClient) FetchPullRequests(owner, repo string, filter *PRFilter,
 		return nil, fmt.Errorf("failed to parse pull request data for %s/%s: %w", owner, repo, err)
 	}
 
-	// Filter by merge date if provided
-	if filter != nil && (filter.StartDate != nil || filter.EndDate != nil) {
-		var filteredPRs []PullRequest
-		for _, pr := range prs {
-			if pr.MergedAt != nil {
-				mergeDate := *pr.MergedAt
-				
-				// Check start date
-				if filter.StartDate != nil && mergeDate.Before(*filter.StartDate) {
-					continue
-				}
-				
-				// Check end date
-				if filter.EndDate != nil && mergeDate.After(*filter.EndDate) {
-					continue
-				}
-				
-				filteredPRs = append(filteredPRs, pr)
-			}
-		}
-		prs = filteredPRs
-	}
+	// Keep the requested states within the date range
+	prs = filterByPeriod(prs, filter)
 
 	return prs, nil
 }
 
-// CheckGitHubCLI checks if GitHub CLI is installed and authenticated
-func (gc *GitHubClient) CheckGitHubCLI() error {
+// ghState returns the gh --state value covering the requested states; "closed" includes merged PRs
+func ghState(filter *PRFilter) string {
+	states := queryStates(filter)
+	switch {
+	case len(states) == 1 && states[0] == stateMerged:
+		return "merged"
+	case len(states) == 1 && states[0] == stateOpen:
+		return "open"
+	case !wantsState(filter, stateOpen):
+		return "closed"
+	default:
+		return "all"
+	}
+}
+
+// CheckGitHubCLI checks if GitHub CLI is installed and authenticated to every given host
+func (gc *GitHubClient) CheckGitHubCLI(hosts []string) error {
 	// Check if gh command exists
 	cmd := exec.Command("gh", "--version")
 	if err := cmd.Run(); err != nil {
 		return fmt.Errorf("GitHub CLI (gh) is not installed or not in PATH: %w", err)
 	}
 
-	// Check if authenticated
-	cmd = exec.Command("gh", "auth", "status")
-	output, err := cmd.Output()
-	if err != nil {
-		return fmt.Errorf("GitHub CLI is not authenticated: %w", err)
+	if len(hosts) == 0 {
+		hosts = []string{defaultHost}
 	}
 
-	// Check if output contains "Logged in"
-	if !strings.Contains(string(output), "Logged in") {
-		return fmt.Errorf("GitHub CLI is not authenticated")
+	for _, host := range hosts {
+		// Check if authenticated
+		cmd = exec.Command("gh", "auth", "status", "--hostname", host)
+		output, err := cmd.CombinedOutput()
+		if err != nil {
+			return fmt.Errorf("GitHub CLI is not authenticated to %s (run `gh auth login --hostname %s`): %w", host, host, err)
+		}
+
+		// Check if output contains "Logged in"
+		if !strings.Contains(string(output), "Logged in") {
+			return fmt.Errorf("GitHub CLI is not authenticated to %s", host)
+		}
 	}
 
 	return nil
@@ -113,48 +133,483 @@ func (gc *GitHubClient) GetDefaultBranch(owner, repo string) (string, error) {
 	return result.DefaultBranchRef.Name, nil
 }
 
-// FetchPullRequestsConcurrent fetches pull requests from multiple repositories concurrently
-func (gc *GitHubClient) FetchPullRequestsConcurrent(repositories []Repository, filter *PRFilter, workerConfig *WorkerConfig) []RepositoryResult {
-	// Create channels for work distribution and results
-	jobs := make(chan Repository, len(repositories))
-	results := make(chan RepositoryResult, len(repositories))
-
-	// Start workers
-	var wg sync.WaitGroup
-	for i := 0; i < workerConfig.MaxWorkers; i++ {
-		wg.Add(1)
-		go func() {
-			defer wg.Done()
-			for repo := range jobs {
-				prs, err := gc.FetchPullRequests(repo.Owner, repo.Name, filter, workerConfig)
-				results <- RepositoryResult{
-					Repository: fmt.Sprintf("%s/%s", repo.Owner, repo.Name),
-					PRs:        prs,
-					Error:      err,
+// prDetailFields lists the `gh pr view` JSON fields captured for evidence packages
+const prDetailFields = "number,title,body,state,url,baseRefName,headRefName,author,mergedBy,createdAt,mergedAt,closedAt," +
+	"reviewDecision,reviews,comments,commits,files,statusCheckRollup,labels,mergeCommit"
+
+// FetchPullRequestEvidence fetches the full record of a single pull request, including
+// inline review comments and timeline events, for an evidence package
+func (gc *GitHubClient) FetchPullRequestEvidence(host, owner, repo string, number int) (*PullRequestEvidence, error) {
+	cmd := exec.Command("gh", "pr", "view", strconv.Itoa(number),
+		"--repo", ghRepoArg(host, owner, repo),
+		"--json", prDetailFields)
+
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch pull request %s/%s#%d: %w", owner, repo, number, err)
+	}
+
+	evidence := &PullRequestEvidence{
+		Repository: fmt.Sprintf("%s/%s", owner, repo),
+		Host:       normalizeHost(host),
+		Raw:        output,
+	}
+	if err := json.Unmarshal(output, &evidence.Detail); err != nil {
+		return nil, fmt.Errorf("failed to parse pull request %s/%s#%d: %w", owner, repo, number, err)
+	}
+
+	evidence.ReviewComments, err = gc.fetchAPIList(host, fmt.Sprintf("repos/%s/%s/pulls/%d/comments", owner, repo, number))
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch review comments for %s/%s#%d: %w", owner, repo, number, err)
+	}
+
+	evidence.Timeline, err = gc.fetchAPIList(host, fmt.Sprintf("repos/%s/%s/issues/%d/timeline", owner, repo, number))
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch timeline for %s/%s#%d: %w", owner, repo, number, err)
+	}
+
+	// Signatures are best effort: the package is still useful without them
+	signatures, err := gc.CommitSignatures(Repository{Host: host, Owner: owner, Name: repo}, PullRequest{Number: number})
+	if err != nil {
+		log.Printf("⚠️ Could not read commit signatures for %s/%s#%d: %v", owner, repo, number, err)
+	} else {
+		applySignatures(evidence.Detail.Commits, evidence.Detail.MergeCommit, signatures)
+	}
+
+	return evidence, nil
+}
+
+// fetchAPIList calls a paginated REST endpoint that returns a JSON array and
+// merges all pages into a single array
+func (gc *GitHubClient) fetchAPIList(host, path string) (json.RawMessage, error) {
+	cmd := exec.Command("gh", "api", "--hostname", normalizeHost(host), "--paginate", path)
+
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, err
+	}
+
+	// --paginate prints one JSON array per page back to back
+	var all []json.RawMessage
+	decoder := json.NewDecoder(bytes.NewReader(output))
+	for decoder.More() {
+		var page []json.RawMessage
+		if err := decoder.Decode(&page); err != nil {
+			return nil, fmt.Errorf("failed to parse response from %s: %w", path, err)
+		}
+		all = append(all, page...)
+	}
+	if all == nil {
+		all = []json.RawMessage{}
+	}
+
+	return json.MarshalIndent(all, "", "  ")
+}
+
+// fetchAPIListSince fetches a REST API list sorted newest first one page at a time, stopping
+// after the first page that reaches back before a time
+func (gc *GitHubClient) fetchAPIListSince(host, path string, since time.Time) (json.RawMessage, error) {
+	var all []json.RawMessage
+	for page := 1; ; page++ {
+		cmd := exec.Command("gh", "api", "--hostname", normalizeHost(host), fmt.Sprintf("%s?per_page=100&page=%d", path, page))
+
+		output, err := cmd.Output()
+		if err != nil {
+			return nil, err
+		}
+
+		var items []json.RawMessage
+		if err := json.Unmarshal(output, &items); err != nil {
+			return nil, fmt.Errorf("failed to parse response from %s: %w", path, err)
+		}
+		all = append(all, items...)
+		if len(items) < 100 {
+			break
+		}
+
+		var last struct {
+			CreatedAt time.Time `json:"created_at"`
+		}
+		if err := json.Unmarshal(items[len(items)-1], &last); err != nil || last.CreatedAt.Before(since) {
+			break
+		}
+	}
+	if all == nil {
+		all = []json.RawMessage{}
+	}
+
+	return json.Marshal(all)
+}
+
+// graphQL runs a GraphQL query with string variables, leaving out empty ones, and decodes its data
+func (gc *GitHubClient) graphQL(host, query string, variables map[string]string, data interface{}) error {
+	cmd := exec.Command("gh", "api", "graphql", "--hostname", normalizeHost(host), "-f", "query="+query)
+	for name, value := range variables {
+		if value != "" {
+			cmd.Args = append(cmd.Args, "-f", name+"="+value)
+		}
+	}
+
+	output, err := cmd.Output()
+	if err != nil {
+		return err
+	}
+
+	var result struct {
+		Data json.RawMessage `json:"data"`
+	}
+	if err := json.Unmarshal(output, &result); err != nil {
+		return fmt.Errorf("failed to parse GraphQL response: %w", err)
+	}
+	return json.Unmarshal(result.Data, data)
+}
+
+// ReadFile returns the contents of a file at a commit
+func (gc *GitHubClient) ReadFile(repository Repository, ref, path string) ([]byte, error) {
+	cmd := exec.Command("gh", "api", "--hostname", normalizeHost(repository.Host),
+		"-H", "Accept: application/vnd.github.raw",
+		fmt.Sprintf("repos/%s/%s/contents/%s?ref=%s", repository.Owner, repository.Name, path, url.QueryEscape(ref)))
+
+	output, err := cmd.Output()
+	if err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok && strings.Contains(string(exitErr.Stderr), "HTTP 404") {
+			return nil, fmt.Errorf("%s at %s: %w", path, shortSHA(ref), os.ErrNotExist)
+		}
+		return nil, fmt.Errorf("failed to read %s at %s: %w", path, shortSHA(ref), err)
+	}
+	return output, nil
+}
+
+// TeamMembers returns the logins of the members of an organization team
+func (gc *GitHubClient) TeamMembers(host, org, team string) ([]string, error) {
+	cmd := exec.Command("gh", "api", "--hostname", normalizeHost(host), "--paginate",
+		fmt.Sprintf("orgs/%s/teams/%s/members", org, team), "--jq", ".[].login")
+
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list members of %s/%s: %w", org, team, err)
+	}
+	return strings.Fields(string(output)), nil
+}
+
+// Releases returns the published, non-prerelease releases of a repository since a time, or its
+// tags when it publishes no releases
+func (gc *GitHubClient) Releases(repository Repository, since time.Time) ([]Release, error) {
+	output, err := gc.fetchAPIListSince(repository.Host, fmt.Sprintf("repos/%s/%s/releases", repository.Owner, repository.Name), since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list releases: %w", err)
+	}
+
+	var items []struct {
+		TagName     string     `json:"tag_name"`
+		Draft       bool       `json:"draft"`
+		Prerelease  bool       `json:"prerelease"`
+		PublishedAt *time.Time `json:"published_at"`
+	}
+	if err := json.Unmarshal(output, &items); err != nil {
+		return nil, fmt.Errorf("failed to parse releases: %w", err)
+	}
+	if len(items) == 0 {
+		return gc.tagReleases(repository, since)
+	}
+
+	var releases []Release
+	for _, item := range items {
+		if item.Draft || item.Prerelease || item.PublishedAt == nil || item.PublishedAt.Before(since) {
+			continue
+		}
+		releases = append(releases, Release{Tag: item.TagName, PublishedAt: *item.PublishedAt})
+	}
+	return releases, nil
+}
+
+// tagsQuery fetches a page of a repository's tags, newest commit first, with the date of the
+// tag for annotated tags and of the tagged commit otherwise
+const tagsQuery = `query($owner: String!, $name: String!, $after: String) {
+  repository(owner: $owner, name: $name) {
+    refs(refPrefix: "refs/tags/", first: 100, after: $after, orderBy: {field: TAG_COMMIT_DATE, direction: DESC}) {
+      nodes {
+        name
+        target {
+          ... on Commit { committedDate }
+          ... on Tag { tagger { date } target { ... on Commit { committedDate } } }
+        }
+      }
+      pageInfo { hasNextPage endCursor }
+    }
+  }
+}`
+
+// tagReleases returns the tags of a repository whose tagged commit is from a time on, for
+// repositories that only push tags
+func (gc *GitHubClient) tagReleases(repository Repository, since time.Time) ([]Release, error) {
+	var releases []Release
+	for after := ""; ; {
+		var data struct {
+			Repository struct {
+				Refs struct {
+					Nodes []struct {
+						Name   string `json:"name"`
+						Target struct {
+							CommittedDate *time.Time `json:"committedDate"`
+							Tagger        *struct {
+								Date time.Time `json:"date"`
+							} `json:"tagger"`
+							Target *struct {
+								CommittedDate *time.Time `json:"committedDate"`
+							} `json:"target"`
+						} `json:"target"`
+					} `json:"nodes"`
+					PageInfo struct {
+						HasNextPage bool   `json:"hasNextPage"`
+						EndCursor   string `json:"endCursor"`
+					} `json:"pageInfo"`
+				} `json:"refs"`
+			} `json:"repository"`
+		}
+		err := gc.graphQL(repository.Host, tagsQuery, map[string]string{"owner": repository.Owner, "name": repository.Name, "after": after}, &data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list tags: %w", err)
+		}
+
+		refs := data.Repository.Refs
+		reachedSince := false
+		for _, node := range refs.Nodes {
+			committedAt := node.Target.CommittedDate
+			if node.Target.Target != nil {
+				committedAt = node.Target.Target.CommittedDate
+			}
+			if committedAt == nil {
+				continue
+			}
+			if committedAt.Before(since) {
+				reachedSince = true
+				continue
+			}
+			taggedAt := *committedAt
+			if node.Target.Tagger != nil {
+				taggedAt = node.Target.Tagger.Date
+			}
+			releases = append(releases, Release{Tag: node.Name, PublishedAt: taggedAt})
+		}
+		if reachedSince || !refs.PageInfo.HasNextPage {
+			break
+		}
+		after = refs.PageInfo.EndCursor
+	}
+	return releases, nil
+}
+
+// deploymentsQuery fetches a page of the deployments to an environment, newest first, with their statuses
+const deploymentsQuery = `query($owner: String!, $name: String!, $environment: String!, $after: String) {
+  repository(owner: $owner, name: $name) {
+    deployments(environments: [$environment], first: 100, after: $after, orderBy: {field: CREATED_AT, direction: DESC}) {
+      nodes { commitOid createdAt statuses(first: 100) { nodes { state createdAt } } }
+      pageInfo { hasNextPage endCursor }
+    }
+  }
+}`
+
+// Deployments returns the successful deployments to an environment since a time, dated by
+// their first success status
+func (gc *GitHubClient) Deployments(repository Repository, environment string, since time.Time) ([]Deployment, error) {
+	var deployments []Deployment
+	for after := ""; ; {
+		var data struct {
+			Repository struct {
+				Deployments struct {
+					Nodes []struct {
+						CommitOid string    `json:"commitOid"`
+						CreatedAt time.Time `json:"createdAt"`
+						Statuses  struct {
+							Nodes []struct {
+								State     string    `json:"state"`
+								CreatedAt time.Time `json:"createdAt"`
+							} `json:"nodes"`
+						} `json:"statuses"`
+					} `json:"nodes"`
+					PageInfo struct {
+						HasNextPage bool   `json:"hasNextPage"`
+						EndCursor   string `json:"endCursor"`
+					} `json:"pageInfo"`
+				} `json:"deployments"`
+			} `json:"repository"`
+		}
+		variables := map[string]string{"owner": repository.Owner, "name": repository.Name, "environment": environment, "after": after}
+		if err := gc.graphQL(repository.Host, deploymentsQuery, variables, &data); err != nil {
+			return nil, fmt.Errorf("failed to list deployments: %w", err)
+		}
+
+		page := data.Repository.Deployments
+		reachedSince := false
+		for _, node := range page.Nodes {
+			if node.CreatedAt.Before(since) {
+				reachedSince = true
+				continue
+			}
+			var succeeded *time.Time
+			for _, status := range node.Statuses.Nodes {
+				if status.State == "SUCCESS" && (succeeded == nil || status.CreatedAt.Before(*succeeded)) {
+					createdAt := status.CreatedAt
+					succeeded = &createdAt
 				}
 			}
-		}()
+			if succeeded != nil && node.CommitOid != "" {
+				deployments = append(deployments, Deployment{SHA: node.CommitOid, DeployedAt: *succeeded})
+			}
+		}
+		if reachedSince || !page.PageInfo.HasNextPage {
+			break
+		}
+		after = page.PageInfo.EndCursor
+	}
+	return deployments, nil
+}
+
+// ContainsCommit reports whether a commit is part of the history of a ref
+func (gc *GitHubClient) ContainsCommit(repository Repository, ref, sha string) (bool, error) {
+	cmd := exec.Command("gh", "api", "--hostname", normalizeHost(repository.Host),
+		fmt.Sprintf("repos/%s/%s/compare/%s...%s", repository.Owner, repository.Name, sha, url.PathEscape(ref)), "--jq", ".status")
+
+	output, err := cmd.Output()
+	if err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok && strings.Contains(string(exitErr.Stderr), "HTTP 404") {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to compare %s with %s: %w", shortSHA(sha), ref, err)
 	}
 
-	// Send jobs to workers
-	go func() {
-		defer close(jobs)
-		for _, repo := range repositories {
-			jobs <- repo
+	// The ref contains the commit when it is ahead of it or is the commit itself
+	status := strings.TrimSpace(string(output))
+	return status == "ahead" || status == "identical", nil
+}
+
+// commitSignaturesQuery fetches the signature of a PR's merge commit and one page of its commits
+const commitSignaturesQuery = `query($owner: String!, $name: String!, $number: Int!, $after: String) {
+  repository(owner: $owner, name: $name) {
+    pullRequest(number: $number) {
+      mergeCommit { oid signature { ...signature } }
+      commits(first: 100, after: $after) {
+        nodes { commit { oid signature { ...signature } } }
+        pageInfo { hasNextPage endCursor }
+      }
+    }
+  }
+}
+fragment signature on GitSignature { state email wasSignedByGitHub signer { login } }`
+
+// gitSignature is a commit signature as returned by the GraphQL API; nil when unsigned
+type gitSignature struct {
+	State             string `json:"state"`
+	Email             string `json:"email"`
+	WasSignedByGitHub bool   `json:"wasSignedByGitHub"`
+	Signer            *Actor `json:"signer"`
+}
+
+// signedCommit is a commit with its signature as returned by the GraphQL API
+type signedCommit struct {
+	Oid       string        `json:"oid"`
+	Signature *gitSignature `json:"signature"`
+}
+
+// CommitSignatures returns GitHub's verification of a PR's commits and merge commit
+func (gc *GitHubClient) CommitSignatures(repository Repository, pr PullRequest) (map[string]Signature, error) {
+	var commits []signedCommit
+	for after := ""; ; {
+		cmd := exec.Command("gh", "api", "graphql", "--hostname", normalizeHost(repository.Host),
+			"-f", "owner="+repository.Owner, "-f", "name="+repository.Name, "-F", fmt.Sprintf("number=%d", pr.Number),
+			"-f", "query="+commitSignaturesQuery)
+		if after != "" {
+			cmd.Args = append(cmd.Args, "-f", "after="+after)
+		}
+
+		output, err := cmd.Output()
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch commit signatures: %w", err)
 		}
-	}()
 
-	// Close results channel when all workers are done
-	go func() {
-		wg.Wait()
-		close(results)
-	}()
+		var result struct {
+			Data struct {
+				Repository struct {
+					PullRequest struct {
+						MergeCommit *signedCommit `json:"mergeCommit"`
+						Commits     struct {
+							Nodes []struct {
+								Commit signedCommit `json:"commit"`
+							} `json:"nodes"`
+							PageInfo struct {
+								HasNextPage bool   `json:"hasNextPage"`
+								EndCursor   string `json:"endCursor"`
+							} `json:"pageInfo"`
+						} `json:"commits"`
+					} `json:"pullRequest"`
+				} `json:"repository"`
+			} `json:"data"`
+		}
+		if err := json.Unmarshal(output, &result); err != nil {
+			return nil, fmt.Errorf("failed to parse commit signatures: %w", err)
+		}
 
-	// Collect results
-	var allResults []RepositoryResult
-	for result := range results {
-		allResults = append(allResults, result)
+		pull := result.Data.Repository.PullRequest
+		if after == "" && pull.MergeCommit != nil {
+			commits = append(commits, *pull.MergeCommit)
+		}
+		for _, node := range pull.Commits.Nodes {
+			commits = append(commits, node.Commit)
+		}
+		if !pull.Commits.PageInfo.HasNextPage {
+			break
+		}
+		after = pull.Commits.PageInfo.EndCursor
+	}
+
+	signatures := make(map[string]Signature)
+	for _, commit := range commits {
+		if commit.Signature == nil {
+			signatures[commit.Oid] = Signature{Reason: "unsigned"}
+			continue
+		}
+		signature := Signature{Verified: commit.Signature.State == "VALID", Reason: strings.ToLower(commit.Signature.State)}
+		switch {
+		case commit.Signature.WasSignedByGitHub:
+			signature.Signer = "GitHub"
+		case commit.Signature.Signer != nil && commit.Signature.Signer.Login != "":
+			signature.Signer = commit.Signature.Signer.Login
+		default:
+			signature.Signer = commit.Signature.Email
+		}
+		signatures[commit.Oid] = signature
 	}
+	return signatures, nil
+}
 
-	return allResults
+// CurrentUser returns the login of the authenticated GitHub CLI user on a host
+func (gc *GitHubClient) CurrentUser(host string) (string, error) {
+	cmd := exec.Command("gh", "api", "--hostname", normalizeHost(host), "user", "--jq", ".login")
+
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to get authenticated user: %w", err)
+	}
+
+	return strings.TrimSpace(string(output)), nil
+}
+
+// normalizeHost strips any scheme and trailing slash from a host, defaulting to github.com
+func normalizeHost(host string) string {
+	host = strings.TrimPrefix(strings.TrimPrefix(strings.TrimSpace(host), "https://"), "http://")
+	host = strings.ToLower(strings.TrimSuffix(host, "/"))
+	if host == "" {
+		return defaultHost
+	}
+	return host
+}
+
+// ghRepoArg builds the --repo argument for gh, prefixing the host for non-default hosts
+func ghRepoArg(host, owner, repo string) string {
+	host = normalizeHost(host)
+	if host == defaultHost {
+		return fmt.Sprintf("%s/%s", owner, repo)
+	}
+	return fmt.Sprintf("%s/%s/%s", host, owner, repo)
 }
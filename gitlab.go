@@ -0,0 +1,288 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxGitLabPageSize is the largest per_page value the GitLab API accepts
+const maxGitLabPageSize = 100
+
+// GitLabClient fetches merged merge requests from the GitLab REST API
+type GitLabClient struct {
+	httpClient *http.Client
+	token      string
+}
+
+// gitLabUser is a user reference in GitLab API responses
+type gitLabUser struct {
+	Username string `json:"username"`
+}
+
+// gitLabMergeRequest is the subset of a GitLab merge request used by the report
+type gitLabMergeRequest struct {
+	IID          int         `json:"iid"`
+	Title        string      `json:"title"`
+	State        string      `json:"state"`
+	CreatedAt    time.Time   `json:"created_at"`
+	MergedAt     *time.Time  `json:"merged_at"`
+	ClosedAt     *time.Time  `json:"closed_at"`
+	Author       gitLabUser  `json:"author"`
+	MergedBy     *gitLabUser `json:"merged_by"`  // Deprecated by GitLab in favour of merge_user
+	MergeUser    *gitLabUser `json:"merge_user"` // Available since GitLab 14.7
+	TargetBranch string      `json:"target_branch"`
+	WebURL       string      `json:"web_url"`
+	Description  string      `json:"description"`
+	Labels       []string    `json:"labels"`
+}
+
+// gitLabApprovals is the response of the merge request approvals endpoint
+type gitLabApprovals struct {
+	ApprovedBy []struct {
+		User gitLabUser `json:"user"`
+	} `json:"approved_by"`
+}
+
+// gitLabDiff is a changed file of a merge request
+type gitLabDiff struct {
+	OldPath     string `json:"old_path"`
+	NewPath     string `json:"new_path"`
+	DeletedFile bool   `json:"deleted_file"`
+	Diff        string `json:"diff"`
+	TooLarge    bool   `json:"too_large"` // Set when GitLab left the diff out for its size
+	Collapsed   bool   `json:"collapsed"` // Set when GitLab collapsed the diff past its display limits
+}
+
+// gitLabCommit is a commit of a merge request
+type gitLabCommit struct {
+	ID            string    `json:"id"`
+	Title         string    `json:"title"`
+	Message       string    `json:"message"`
+	AuthoredDate  time.Time `json:"authored_date"`
+	CommittedDate time.Time `json:"committed_date"`
+}
+
+// NewGitLabClient creates a GitLab client authenticated with GITLAB_TOKEN when it is set
+func NewGitLabClient() *GitLabClient {
+	return &GitLabClient{
+		httpClient: &http.Client{Timeout: 60 * time.Second},
+		token:      os.Getenv("GITLAB_TOKEN"),
+	}
+}
+
+// FetchPullRequests fetches the merge requests of a project in the requested states and maps them to pull requests
+func (gl *GitLabClient) FetchPullRequests(repository Repository, filter *PRFilter, workerConfig *WorkerConfig) ([]PullRequest, error) {
+	project := url.PathEscape(repository.Owner + "/" + repository.Name)
+
+	perPage := maxGitLabPageSize
+	if workerConfig != nil && workerConfig.PageSize > 0 && workerConfig.PageSize < perPage {
+		perPage = workerConfig.PageSize
+	}
+
+	query := url.Values{}
+	query.Set("state", gitLabQueryState(filter))
+	query.Set("order_by", "updated_at")
+	query.Set("sort", "desc")
+	query.Set("per_page", strconv.Itoa(perPage))
+	if filter != nil && filter.StartDate != nil && !wantsState(filter, stateOpen) {
+		// A merge request is updated when it is merged or closed, so this never drops PRs closed in range;
+		// open merge requests may not have been updated in the period at all
+		query.Set("updated_after", filter.StartDate.Format(time.RFC3339))
+	}
+	if role, login, ok := singleScopedLogin(filter); ok && role == roleAuthored {
+		query.Set("author_username", login)
+	}
+
+	var mergeRequests []gitLabMergeRequest
+	for page := "1"; page != ""; {
+		query.Set("page", page)
+		var batch []gitLabMergeRequest
+		header, err := gl.get(repository.BaseURL, fmt.Sprintf("/projects/%s/merge_requests?%s", project, query.Encode()), &batch)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch merge requests for %s/%s: %w", repository.Owner, repository.Name, err)
+		}
+		mergeRequests = append(mergeRequests, batch...)
+
+		if filter != nil && filter.Limit > 0 && len(mergeRequests) >= filter.Limit {
+			mergeRequests = mergeRequests[:filter.Limit]
+			break
+		}
+		page = header.Get("X-Next-Page")
+	}
+
+	var prs []PullRequest
+	for _, mr := range mergeRequests {
+		pr := PullRequest{
+			Number:      mr.IID,
+			Title:       mr.Title,
+			State:       gitLabState(mr.State),
+			MergedAt:    mr.MergedAt,
+			ClosedAt:    mr.ClosedAt,
+			CreatedAt:   mr.CreatedAt,
+			URL:         mr.WebURL,
+			BaseRefName: mr.TargetBranch,
+			Body:        mr.Description,
+		}
+		for _, label := range mr.Labels {
+			pr.Labels = append(pr.Labels, Label{Name: label})
+		}
+		pr.Author.Login = mr.Author.Username
+		if mr.MergeUser != nil {
+			pr.MergedBy = &Actor{Login: mr.MergeUser.Username}
+		} else if mr.MergedBy != nil {
+			pr.MergedBy = &Actor{Login: mr.MergedBy.Username}
+		}
+		prs = append(prs, pr)
+	}
+	prs = filterByPeriod(prs, filter)
+
+	// Approvals, diffs and commits are only fetched for merge requests that made it through the date
+	// filter. A merge request whose details cannot be fetched is reported as unverified.
+	for i := range prs {
+		ref := fmt.Sprintf("%s/%s!%d", repository.Owner, repository.Name, prs[i].Number)
+		path := fmt.Sprintf("/projects/%s/merge_requests/%d", project, prs[i].Number)
+
+		var approvals gitLabApprovals
+		if _, err := gl.get(repository.BaseURL, path+"/approvals", &approvals); err != nil {
+			markUnverified(&prs[i], ref, partReviews, err)
+		}
+		for _, approval := range approvals.ApprovedBy {
+			// GitLab does not timestamp approvals; an approval still present when listed was given before the merge
+			review := Review{Author: Actor{Login: approval.User.Username}, State: "APPROVED"}
+			if prs[i].MergedAt != nil {
+				review.SubmittedAt = *prs[i].MergedAt
+			}
+			prs[i].Reviews = append(prs[i].Reviews, review)
+		}
+
+		if err := gl.fetchDiffs(repository.BaseURL, path+"/diffs", &prs[i]); err != nil {
+			markUnverified(&prs[i], ref, partFiles, err)
+		}
+
+		commits, err := gl.fetchCommits(repository.BaseURL, path+"/commits")
+		if err != nil {
+			markUnverified(&prs[i], ref, partCommits, err)
+		}
+		prs[i].Commits = commits
+	}
+
+	return prs, nil
+}
+
+// fetchDiffs sets the changed files and line counts of a merge request from its diffs
+func (gl *GitLabClient) fetchDiffs(baseURL, path string, pr *PullRequest) error {
+	var files []ChangedFile
+	var additions, deletions int
+	linesUnknown := false
+	for page := "1"; page != ""; {
+		var batch []gitLabDiff
+		header, err := gl.get(baseURL, fmt.Sprintf("%s?per_page=%d&page=%s", path, maxGitLabPageSize, page), &batch)
+		if err != nil {
+			return err
+		}
+		for _, diff := range batch {
+			file := ChangedFile{Path: diff.NewPath}
+			if diff.DeletedFile {
+				file.Path = diff.OldPath
+			}
+			// Diffs past GitLab's size limits are left out, so their lines cannot be counted
+			if diff.TooLarge || (diff.Collapsed && diff.Diff == "") {
+				linesUnknown = true
+			}
+			file.Additions, file.Deletions = diffLineCounts(diff.Diff)
+			files = append(files, file)
+			additions += file.Additions
+			deletions += file.Deletions
+		}
+		page = header.Get("X-Next-Page")
+	}
+
+	pr.Files, pr.ChangedFiles = files, len(files)
+	pr.Additions, pr.Deletions = additions, deletions
+	pr.LinesUnknown = linesUnknown
+	return nil
+}
+
+// fetchCommits returns the commits of a merge request, oldest first like gh
+func (gl *GitLabClient) fetchCommits(baseURL, path string) ([]PRCommit, error) {
+	var commits []PRCommit
+	for page := "1"; page != ""; {
+		var batch []gitLabCommit
+		header, err := gl.get(baseURL, fmt.Sprintf("%s?per_page=%d&page=%s", path, maxGitLabPageSize, page), &batch)
+		if err != nil {
+			return nil, err
+		}
+		for _, commit := range batch {
+			_, body, _ := strings.Cut(strings.TrimSpace(commit.Message), "\n")
+			commits = append(commits, PRCommit{
+				Oid:             commit.ID,
+				MessageHeadline: commit.Title,
+				MessageBody:     strings.TrimSpace(body),
+				AuthoredDate:    commit.AuthoredDate,
+				CommittedDate:   commit.CommittedDate,
+			})
+		}
+		page = header.Get("X-Next-Page")
+	}
+
+	// GitLab lists merge request commits newest first
+	for i, j := 0, len(commits)-1; i < j; i, j = i+1, j-1 {
+		commits[i], commits[j] = commits[j], commits[i]
+	}
+	return commits, nil
+}
+
+// diffLineCounts counts the added and deleted lines of a unified diff without file headers
+func diffLineCounts(diff string) (additions, deletions int) {
+	for _, line := range strings.Split(diff, "\n") {
+		switch {
+		case strings.HasPrefix(line, "+"):
+			additions++
+		case strings.HasPrefix(line, "-"):
+			deletions++
+		}
+	}
+	return additions, deletions
+}
+
+// get performs an authenticated GET against the GitLab v4 API and decodes the JSON response
+func (gl *GitLabClient) get(baseURL, path string, result interface{}) (http.Header, error) {
+	header := http.Header{}
+	if gl.token != "" {
+		header.Set("PRIVATE-TOKEN", gl.token)
+	}
+	return getJSON(gl.httpClient, strings.TrimSuffix(baseURL, "/")+"/api/v4"+path, header, result)
+}
+
+// gitLabQueryState returns the merge request state parameter covering the requested states
+func gitLabQueryState(filter *PRFilter) string {
+	states := queryStates(filter)
+	if len(states) > 1 {
+		return "all"
+	}
+	switch states[0] {
+	case stateClosed:
+		return "closed"
+	case stateOpen:
+		return "opened"
+	default:
+		return "merged"
+	}
+}
+
+// gitLabState maps GitLab merge request states to the GitHub states used in reports
+func gitLabState(state string) string {
+	switch state {
+	case "merged":
+		return "MERGED"
+	case "opened":
+		return "OPEN"
+	default:
+		return strings.ToUpper(state)
+	}
+}
@@ -0,0 +1,154 @@
+package main
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+const gitLabTestProject = "/api/v4/projects/acme%2Fapi/merge_requests"
+
+// gitLabTestResponses returns a project with two merged merge requests listed over two pages
+func gitLabTestResponses() map[string]fakeResponse {
+	return map[string]fakeResponse{
+		gitLabTestProject + "?order_by=updated_at&page=1&per_page=100&sort=desc&state=merged": {
+			Header: map[string]string{"X-Next-Page": "2"},
+			Body: `[{"iid": 2, "title": "Add billing", "state": "merged", "created_at": "2024-03-01T10:00:00Z",
+				"merged_at": "2024-03-02T10:00:00Z", "author": {"username": "alice"}, "merge_user": {"username": "bob"},
+				"target_branch": "main", "web_url": "https://gitlab.example.com/acme/api/-/merge_requests/2", "labels": ["hotfix"]}]`,
+		},
+		gitLabTestProject + "?order_by=updated_at&page=2&per_page=100&sort=desc&state=merged": {
+			Body: `[{"iid": 1, "title": "Initial", "state": "merged", "created_at": "2024-02-01T10:00:00Z",
+				"merged_at": "2024-02-02T10:00:00Z", "author": {"username": "carol"}, "merged_by": {"username": "carol"}}]`,
+		},
+		gitLabTestProject + "/2/approvals": {
+			Body: `{"approved_by": [{"user": {"username": "bob"}}, {"user": {"username": "dave"}}]}`,
+		},
+		gitLabTestProject + "/1/approvals": {
+			Body: `{"approved_by": []}`,
+		},
+		gitLabTestProject + "/2/diffs?per_page=100&page=1": {
+			Header: map[string]string{"X-Next-Page": "2"},
+			Body:   `[{"old_path": "app.go", "new_path": "app.go", "diff": "@@ -1,2 +1,3 @@\n context\n-old\n+new\n+more\n"}]`,
+		},
+		gitLabTestProject + "/2/diffs?per_page=100&page=2": {
+			Body: `[{"old_path": "gone.go", "new_path": "gone.go", "deleted_file": true, "diff": "@@ -1 +0,0 @@\n-gone\n"}]`,
+		},
+		gitLabTestProject + "/1/diffs?per_page=100&page=1": {
+			Body: `[{"old_path": "data.sql", "new_path": "data.sql", "diff": "", "too_large": true}]`,
+		},
+		gitLabTestProject + "/2/commits?per_page=100&page=1": {
+			Body: `[{"id": "bbb", "title": "Second", "message": "Second\n\nDetails", "committed_date": "2024-03-01T12:00:00Z"},
+				{"id": "aaa", "title": "First", "message": "First", "committed_date": "2024-03-01T11:00:00Z"}]`,
+		},
+		gitLabTestProject + "/1/commits?per_page=100&page=1": {
+			Body: `[{"id": "ccc", "title": "Initial", "message": "Initial"}]`,
+		},
+	}
+}
+
+func TestGitLabFetchPullRequests(t *testing.T) {
+	server := newFakeAPI(t, gitLabTestResponses())
+	client := &GitLabClient{httpClient: server.Client()}
+
+	prs, err := client.FetchPullRequests(Repository{Owner: "acme", Name: "api", BaseURL: server.URL}, nil, nil)
+	if err != nil {
+		t.Fatalf("FetchPullRequests: %v", err)
+	}
+	if got := pullRequestNumbers(prs); !reflect.DeepEqual(got, []int{2, 1}) {
+		t.Fatalf("numbers = %v, want [2 1] from both pages", got)
+	}
+
+	pr := prs[0]
+	if pr.State != "MERGED" || pr.Author.Login != "alice" || mergedByLogin(pr) != "bob" || pr.BaseRefName != "main" {
+		t.Errorf("mapped state %q, author %q, merged by %q, base %q", pr.State, pr.Author.Login, mergedByLogin(pr), pr.BaseRefName)
+	}
+	if len(pr.Labels) != 1 || pr.Labels[0].Name != "hotfix" {
+		t.Errorf("labels = %v, want [hotfix]", pr.Labels)
+	}
+	if got := approvers(pr); !reflect.DeepEqual(got, []string{"bob", "dave"}) {
+		t.Errorf("approvers = %v, want [bob dave]", got)
+	}
+	if got := filePaths(pr.Files); !reflect.DeepEqual(got, []string{"app.go", "gone.go"}) {
+		t.Errorf("files = %v, want both diff pages", got)
+	}
+	if pr.Additions != 2 || pr.Deletions != 2 || pr.ChangedFiles != 2 || pr.LinesUnknown || pr.FilesIncomplete {
+		t.Errorf("size +%d/-%d in %d files (lines unknown %v, files incomplete %v), want +2/-2 in 2 complete files",
+			pr.Additions, pr.Deletions, pr.ChangedFiles, pr.LinesUnknown, pr.FilesIncomplete)
+	}
+	if got := commitOids(pr.Commits); !reflect.DeepEqual(got, []string{"aaa", "bbb"}) {
+		t.Errorf("commits = %v, want oldest first", got)
+	}
+	if pr.Commits[1].MessageBody != "Details" {
+		t.Errorf("commit body = %q, want the message without its title", pr.Commits[1].MessageBody)
+	}
+
+	if !prs[1].LinesUnknown || mergedByLogin(prs[1]) != "carol" {
+		t.Errorf("merge request with a too large diff: lines unknown %v, merged by %q", prs[1].LinesUnknown, mergedByLogin(prs[1]))
+	}
+	for _, pr := range prs {
+		if len(pr.Unverified) > 0 {
+			t.Errorf("!%d unverified: %v", pr.Number, pr.Unverified)
+		}
+	}
+}
+
+func TestGitLabFetchPullRequestsMarksFailedDetailsUnverified(t *testing.T) {
+	tests := []struct {
+		name   string
+		path   string
+		verify func(t *testing.T, pr PullRequest)
+	}{
+		{"approvals", "/2/approvals", func(t *testing.T, pr PullRequest) {
+			if !pr.ReviewsUnknown || len(pr.Reviews) != 0 {
+				t.Errorf("reviews unknown %v with %d reviews, want unknown without reviews", pr.ReviewsUnknown, len(pr.Reviews))
+			}
+		}},
+		{"diffs", "/2/diffs?per_page=100&page=2", func(t *testing.T, pr PullRequest) {
+			if !pr.FilesIncomplete || !pr.LinesUnknown {
+				t.Errorf("files incomplete %v, lines unknown %v, want both", pr.FilesIncomplete, pr.LinesUnknown)
+			}
+		}},
+		{"commits", "/2/commits?per_page=100&page=1", func(t *testing.T, pr PullRequest) {
+			if len(pr.Commits) != 0 {
+				t.Errorf("commits = %v, want none", commitOids(pr.Commits))
+			}
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			responses := gitLabTestResponses()
+			responses[gitLabTestProject+tt.path] = fakeResponse{Status: http.StatusInternalServerError}
+			server := newFakeAPI(t, responses)
+			client := &GitLabClient{httpClient: server.Client()}
+
+			prs, err := client.FetchPullRequests(Repository{Owner: "acme", Name: "api", BaseURL: server.URL}, nil, nil)
+			if err != nil {
+				t.Fatalf("FetchPullRequests failed for the whole repository: %v", err)
+			}
+			if len(prs) != 2 {
+				t.Fatalf("got %d merge requests, want 2", len(prs))
+			}
+			if len(prs[0].Unverified) != 1 || len(prs[1].Unverified) != 0 {
+				t.Errorf("unverified = %v and %v, want only the failed part of !2", prs[0].Unverified, prs[1].Unverified)
+			}
+			tt.verify(t, prs[0])
+
+			exceptions := evaluateControls(PRRecord{Repository: "acme/api", Provider: providerGitLab, PR: prs[0]})
+			if !hasException(exceptions, ExceptionNotVerified) {
+				t.Errorf("exceptions = %v, want %s", exceptions, ExceptionNotVerified)
+			}
+		})
+	}
+}
+
+// hasException reports whether exceptions include one of a type
+func hasException(exceptions []Exception, exceptionType string) bool {
+	for _, exception := range exceptions {
+		if exception.Type == exceptionType {
+			return true
+		}
+	}
+	return false
+}
@@ -4,6 +4,7 @@ go 1.21
 
 require (
 	github.com/spf13/cobra v1.8.0
+	github.com/tealeg/xlsx/v3 v3.3.13
 	gopkg.in/yaml.v3 v3.0.1
 )
 
@@ -19,6 +20,5 @@ require (
 	github.com/rogpeppe/go-internal v1.9.0 // indirect
 	github.com/shabbyrobe/xmlwriter v0.0.0-20200208144257-9fca06d00ffa // indirect
 	github.com/spf13/pflag v1.0.5 // indirect
-	github.com/tealeg/xlsx/v3 v3.3.13 // indirect
 	golang.org/x/text v0.3.8 // indirect
 )
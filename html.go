@@ -0,0 +1,452 @@
+package main
+
+import (
+	"fmt"
+	"html/template"
+	"log"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// htmlReport is the data rendered into the HTML dashboard
+type htmlReport struct {
+	Generated       string
+	RepositoryCount int
+	RepositoryRows  []htmlSummary
+	VerticalRows    []htmlSummary
+	PRCount         int
+	ExceptionCount  int
+	Columns         []string
+	Rows            []htmlRow
+	Chart           htmlChart
+	StateSections   []htmlStateSection
+}
+
+// htmlRow is a single pull request row in the dashboard table
+type htmlRow struct {
+	URL        string   `json:"url"`
+	Cells      []string `json:"cells"`
+	Repository string   `json:"repository"`
+	Verticals  []string `json:"verticals"`
+	Exceptions []string `json:"exceptions"`
+}
+
+// htmlStateSection lists the closed-unmerged or open pull requests of the report
+type htmlStateSection struct {
+	Title string
+	Rows  []htmlStateRow
+}
+
+// htmlStateRow is a single closed-unmerged or open pull request
+type htmlStateRow struct {
+	URL        string
+	Repository string
+	Number     int
+	Title      string
+	Author     string
+	Opened     string
+	Closed     string
+}
+
+// htmlSummary is a per-repository or per-vertical summary line
+type htmlSummary struct {
+	Name         string
+	Detail       string
+	PRs          int
+	Exceptions   int
+	Authors      int
+	Repositories int
+}
+
+// htmlChart is a pre-computed SVG bar chart of merges over time
+type htmlChart struct {
+	Period string
+	Width  int
+	Height int
+	Bars   []htmlBar
+}
+
+// htmlBar is a single bar of the merges chart
+type htmlBar struct {
+	Label  string
+	Count  int
+	X      int
+	Y      int
+	Width  int
+	Height int
+}
+
+// outputHTML writes a self-contained HTML dashboard next to the markdown report
+func outputHTML(reportFile string, allPRs []PRRecord) {
+	htmlFile := strings.TrimSuffix(reportFile, ".md") + ".html"
+
+	// Only include PRs that are actually merged (have a merge date)
+	var merged []PRRecord
+	for _, item := range allPRs {
+		if item.PR.MergedAt != nil && item.PR.State == "MERGED" {
+			merged = append(merged, item)
+		}
+	}
+	sort.Slice(merged, func(i, j int) bool {
+		return merged[i].PR.MergedAt.After(*merged[j].PR.MergedAt)
+	})
+
+	report := htmlReport{
+		Generated: time.Now().Format("2006-01-02 15:04:05 MST"),
+		PRCount:   len(merged),
+		Chart:     buildMergeChart(merged),
+	}
+	for _, column := range prColumns {
+		report.Columns = append(report.Columns, column.Header)
+	}
+
+	repoSummaries := make(map[string]*htmlSummary)
+	repoAuthors := make(map[string]map[string]bool)
+	verticalSummaries := make(map[string]*htmlSummary)
+	verticalRepos := make(map[string]map[string]bool)
+	verticalAuthors := make(map[string]map[string]bool)
+
+	for _, item := range merged {
+		row := htmlRow{
+			URL:        item.URL(),
+			Repository: item.RepositoryKey(),
+			Verticals:  item.Verticals,
+		}
+		for _, column := range prColumns {
+			row.Cells = append(row.Cells, column.Value(item))
+		}
+		for _, exception := range item.PR.Exceptions {
+			row.Exceptions = append(row.Exceptions, fmt.Sprintf("%s: %s", exception.Type, exception.Detail))
+		}
+		report.Rows = append(report.Rows, row)
+
+		hasException := 0
+		if len(item.PR.Exceptions) > 0 {
+			hasException = 1
+			report.ExceptionCount++
+		}
+
+		key := item.RepositoryKey()
+		repo, ok := repoSummaries[key]
+		if !ok {
+			repo = &htmlSummary{Name: key, Detail: strings.Join(item.Verticals, ", ")}
+			repoSummaries[key] = repo
+			repoAuthors[key] = make(map[string]bool)
+		}
+		repo.PRs++
+		repo.Exceptions += hasException
+		repoAuthors[key][item.PR.Author.Login] = true
+
+		for _, name := range recordVerticals(item) {
+			vertical, ok := verticalSummaries[name]
+			if !ok {
+				vertical = &htmlSummary{Name: name}
+				verticalSummaries[name] = vertical
+				verticalRepos[name] = make(map[string]bool)
+				verticalAuthors[name] = make(map[string]bool)
+			}
+			vertical.PRs++
+			vertical.Exceptions += hasException
+			verticalRepos[name][key] = true
+			verticalAuthors[name][item.PR.Author.Login] = true
+		}
+	}
+
+	for name, summary := range repoSummaries {
+		summary.Authors = len(repoAuthors[name])
+		report.RepositoryRows = append(report.RepositoryRows, *summary)
+	}
+	for name, summary := range verticalSummaries {
+		summary.Repositories = len(verticalRepos[name])
+		summary.Authors = len(verticalAuthors[name])
+		report.VerticalRows = append(report.VerticalRows, *summary)
+	}
+	sort.Slice(report.RepositoryRows, func(i, j int) bool { return report.RepositoryRows[i].Name < report.RepositoryRows[j].Name })
+	sort.Slice(report.VerticalRows, func(i, j int) bool { return report.VerticalRows[i].Name < report.VerticalRows[j].Name })
+	report.RepositoryCount = len(report.RepositoryRows)
+
+	// Closed-unmerged and open PRs, when requested, are separate populations
+	for _, section := range stateSections {
+		records := recordsInState(allPRs, section.State)
+		if len(records) == 0 {
+			continue
+		}
+		stateSection := htmlStateSection{Title: section.Icon + " " + section.Name}
+		for _, item := range records {
+			row := htmlStateRow{
+				URL:        item.URL(),
+				Repository: item.RepositoryKey(),
+				Number:     item.PR.Number,
+				Title:      item.PR.Title,
+				Author:     personName(item.PR.Author.Login),
+				Opened:     item.PR.CreatedAt.Format("2006-01-02"),
+			}
+			if item.PR.ClosedAt != nil {
+				row.Closed = item.PR.ClosedAt.Format("2006-01-02")
+			}
+			stateSection.Rows = append(stateSection.Rows, row)
+		}
+		report.StateSections = append(report.StateSections, stateSection)
+	}
+
+	output, err := os.Create(htmlFile)
+	if err != nil {
+		log.Printf("Failed to create HTML file: %v", err)
+		return
+	}
+	defer output.Close()
+
+	if err := htmlReportTemplate.Execute(output, report); err != nil {
+		log.Printf("Failed to generate HTML report: %v", err)
+		return
+	}
+
+	recordArtifact(htmlFile)
+	fmt.Printf("🌐 HTML report generated: %s\n", htmlFile)
+}
+
+// buildMergeChart buckets merges by week, or by month for periods longer than ~3 months
+func buildMergeChart(merged []PRRecord) htmlChart {
+	chart := htmlChart{Period: "week", Width: 900, Height: 220}
+	if len(merged) == 0 {
+		return chart
+	}
+
+	first, last := *merged[0].PR.MergedAt, *merged[0].PR.MergedAt
+	for _, item := range merged {
+		if item.PR.MergedAt.Before(first) {
+			first = *item.PR.MergedAt
+		}
+		if item.PR.MergedAt.After(last) {
+			last = *item.PR.MergedAt
+		}
+	}
+	if last.Sub(first) > 90*24*time.Hour {
+		chart.Period = "month"
+	}
+
+	bucket := func(t time.Time) time.Time {
+		t = t.UTC()
+		if chart.Period == "month" {
+			return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+		}
+		day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+		return day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7)) // Monday
+	}
+	next := func(t time.Time) time.Time {
+		if chart.Period == "month" {
+			return t.AddDate(0, 1, 0)
+		}
+		return t.AddDate(0, 0, 7)
+	}
+
+	counts := make(map[time.Time]int)
+	for _, item := range merged {
+		counts[bucket(*item.PR.MergedAt)]++
+	}
+
+	// Include empty periods so gaps are visible
+	var periods []time.Time
+	for t := bucket(first); !t.After(bucket(last)); t = next(t) {
+		periods = append(periods, t)
+	}
+
+	maxCount := 0
+	for _, count := range counts {
+		if count > maxCount {
+			maxCount = count
+		}
+	}
+
+	plotHeight := chart.Height - 40
+	barWidth := chart.Width / len(periods)
+	for i, period := range periods {
+		label := period.Format("2006-01-02")
+		if chart.Period == "month" {
+			label = period.Format("2006-01")
+		}
+		height := 0
+		if maxCount > 0 {
+			height = counts[period] * plotHeight / maxCount
+		}
+		chart.Bars = append(chart.Bars, htmlBar{
+			Label:  label,
+			Count:  counts[period],
+			X:      i * barWidth,
+			Y:      plotHeight - height + 15,
+			Width:  barWidth,
+			Height: height,
+		})
+	}
+
+	return chart
+}
+
+var htmlReportTemplate = template.Must(template.New("report").Funcs(template.FuncMap{
+	"sub":  func(a, b int) int { return a - b },
+	"half": func(a int) int { return a / 2 },
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>Merged Pull Request Analysis Report</title>
+<style>
+body { font-family: -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; margin: 2em; color: #1f2328; background: #fff; }
+h1 { margin-bottom: 0.2em; }
+.generated { color: #656d76; margin-bottom: 1.5em; }
+.cards { display: flex; gap: 1em; margin-bottom: 2em; }
+.card { border: 1px solid #d0d7de; border-radius: 6px; padding: 1em 1.5em; min-width: 10em; }
+.card .value { font-size: 2em; font-weight: 600; }
+.card.alert .value { color: #cf222e; }
+table { border-collapse: collapse; margin-bottom: 2em; width: 100%; }
+th, td { border: 1px solid #d0d7de; padding: 4px 8px; text-align: left; vertical-align: top; font-size: 0.9em; }
+th { background: #f6f8fa; }
+#prs th { cursor: pointer; user-select: none; }
+#prs th.asc::after { content: " ▲"; }
+#prs th.desc::after { content: " ▼"; }
+tr.exception td { background: #ffebe9; }
+td.alert { color: #cf222e; font-weight: 600; }
+.filters { display: flex; gap: 1em; align-items: center; margin-bottom: 1em; }
+.filters input[type=search] { width: 20em; padding: 4px; }
+svg text { font-size: 10px; fill: #656d76; }
+svg rect { fill: #0969da; }
+</style>
+</head>
+<body>
+<h1>Merged Pull Request Analysis Report</h1>
+<div class="generated">Generated {{.Generated}}</div>
+
+<div class="cards">
+<div class="card"><div class="value">{{.RepositoryCount}}</div>Repositories with Merged PRs</div>
+<div class="card"><div class="value">{{.PRCount}}</div>Merged Pull Requests</div>
+<div class="card{{if .ExceptionCount}} alert{{end}}"><div class="value">{{.ExceptionCount}}</div>PRs with Exceptions</div>
+</div>
+
+<h2>Merges per {{.Chart.Period}}</h2>
+<svg width="{{.Chart.Width}}" height="{{.Chart.Height}}" role="img" aria-label="Merges per {{.Chart.Period}}">
+{{range .Chart.Bars}}<g><title>{{.Label}}: {{.Count}}</title><rect x="{{.X}}" y="{{.Y}}" width="{{sub .Width 2}}" height="{{.Height}}"></rect>
+{{if .Count}}<text x="{{.X}}" y="{{sub .Y 3}}">{{.Count}}</text>{{end}}</g>
+{{end}}{{if .Chart.Bars}}<text x="0" y="{{sub .Chart.Height 5}}">{{(index .Chart.Bars 0).Label}}</text>
+<text x="{{half .Chart.Width}}" y="{{sub .Chart.Height 5}}" text-anchor="middle">{{len .Chart.Bars}} {{.Chart.Period}}s</text>
+<text x="{{.Chart.Width}}" y="{{sub .Chart.Height 5}}" text-anchor="end">{{(index .Chart.Bars (sub (len .Chart.Bars) 1)).Label}}</text>{{end}}
+</svg>
+
+<h2>Verticals</h2>
+<table>
+<tr><th>Vertical</th><th>Repositories</th><th>Merged PRs</th><th>Authors</th><th>PRs with Exceptions</th></tr>
+{{range .VerticalRows}}<tr><td>{{.Name}}</td><td>{{.Repositories}}</td><td>{{.PRs}}</td><td>{{.Authors}}</td><td{{if .Exceptions}} class="alert"{{end}}>{{.Exceptions}}</td></tr>
+{{end}}</table>
+
+<h2>Repositories</h2>
+<table>
+<tr><th>Repository</th><th>Verticals</th><th>Merged PRs</th><th>Authors</th><th>PRs with Exceptions</th></tr>
+{{range .RepositoryRows}}<tr><td>{{.Name}}</td><td>{{.Detail}}</td><td>{{.PRs}}</td><td>{{.Authors}}</td><td{{if .Exceptions}} class="alert"{{end}}>{{.Exceptions}}</td></tr>
+{{end}}</table>
+
+<h2>Pull Requests</h2>
+<div class="filters">
+<input type="search" id="search" placeholder="Filter pull requests…">
+<select id="repository"><option value="">All repositories</option></select>
+<select id="vertical"><option value="">All verticals</option></select>
+<label><input type="checkbox" id="exceptions"> Exceptions only</label>
+<span id="count"></span>
+</div>
+<table id="prs"><thead><tr></tr></thead><tbody></tbody></table>
+
+{{range .StateSections}}<h2>{{.Title}}</h2>
+<table>
+<tr><th>Repository</th><th>PR</th><th>Title</th><th>Author</th><th>Opened</th><th>Closed</th></tr>
+{{range .Rows}}<tr><td>{{.Repository}}</td><td>{{if .URL}}<a href="{{.URL}}">#{{.Number}}</a>{{else}}#{{.Number}}{{end}}</td><td>{{.Title}}</td><td>{{.Author}}</td><td>{{.Opened}}</td><td>{{.Closed}}</td></tr>
+{{end}}</table>
+{{end}}
+
+<script>
+const columns = {{.Columns}};
+const rows = {{.Rows}} || [];
+let sortColumn = -1, sortDir = 1;
+
+function compare(a, b) {
+  const na = /^#?\d+$/.test(a) ? parseInt(a.replace("#", ""), 10) : NaN;
+  const nb = /^#?\d+$/.test(b) ? parseInt(b.replace("#", ""), 10) : NaN;
+  if (!isNaN(na) && !isNaN(nb)) return na - nb;
+  return a.localeCompare(b);
+}
+
+function render() {
+  const search = document.getElementById("search").value.toLowerCase();
+  const repository = document.getElementById("repository").value;
+  const vertical = document.getElementById("vertical").value;
+  const exceptionsOnly = document.getElementById("exceptions").checked;
+
+  let visible = rows.filter(r =>
+    (!search || r.cells.join(" ").toLowerCase().includes(search)) &&
+    (!repository || r.repository === repository) &&
+    (!vertical || (r.verticals || []).includes(vertical)) &&
+    (!exceptionsOnly || (r.exceptions || []).length > 0));
+  if (sortColumn >= 0) {
+    visible = visible.slice().sort((a, b) => sortDir * compare(a.cells[sortColumn], b.cells[sortColumn]));
+  }
+
+  const tbody = document.querySelector("#prs tbody");
+  tbody.innerHTML = "";
+  for (const r of visible) {
+    const tr = document.createElement("tr");
+    if ((r.exceptions || []).length > 0) {
+      tr.className = "exception";
+      tr.title = r.exceptions.join("\n");
+    }
+    r.cells.forEach((value, i) => {
+      const td = document.createElement("td");
+      if (columns[i] === "PR_Number" && r.url) {
+        const a = document.createElement("a");
+        a.href = r.url;
+        a.textContent = value;
+        td.appendChild(a);
+      } else {
+        td.textContent = value;
+      }
+      tr.appendChild(td);
+    });
+    tbody.appendChild(tr);
+  }
+  document.getElementById("count").textContent = visible.length + " of " + rows.length + " pull requests";
+}
+
+function init() {
+  const head = document.querySelector("#prs thead tr");
+  columns.forEach((name, i) => {
+    const th = document.createElement("th");
+    th.textContent = name;
+    th.addEventListener("click", () => {
+      sortDir = sortColumn === i ? -sortDir : 1;
+      sortColumn = i;
+      head.querySelectorAll("th").forEach(h => h.className = "");
+      th.className = sortDir > 0 ? "asc" : "desc";
+      render();
+    });
+    head.appendChild(th);
+  });
+
+  const addOptions = (id, values) => {
+    const select = document.getElementById(id);
+    [...new Set(values)].sort().forEach(v => {
+      const option = document.createElement("option");
+      option.value = option.textContent = v;
+      select.appendChild(option);
+    });
+  };
+  addOptions("repository", rows.map(r => r.repository));
+  addOptions("vertical", rows.flatMap(r => r.verticals || []));
+
+  ["search", "repository", "vertical", "exceptions"].forEach(id =>
+    document.getElementById(id).addEventListener("input", render));
+  render();
+}
+
+init();
+</script>
+</body>
+</html>
+`))
@@ -0,0 +1,296 @@
+package main
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+var identitiesFile string
+
+// Identity maps a login to the person (or bot) behind it
+type Identity struct {
+	Login     string `yaml:"login"`
+	Name      string `yaml:"name"`
+	Email     string `yaml:"email,omitempty"`
+	Team      string `yaml:"team,omitempty"`
+	StartDate string `yaml:"start_date,omitempty"` // YYYY-MM-DD
+	EndDate   string `yaml:"end_date,omitempty"`   // YYYY-MM-DD, empty while employed
+	Bot       bool   `yaml:"bot,omitempty"`
+
+	end *time.Time
+}
+
+// IdentitiesConfig is the YAML form of the identity mapping file
+type IdentitiesConfig struct {
+	Identities []Identity `yaml:"identities"`
+}
+
+// Identity categories
+const (
+	identityEmployee = "Employee"
+	identityBot      = "Bot"
+	identityUnmapped = "Unmapped"
+)
+
+// identities holds the loaded mapping keyed by lower-case login; nil when no mapping file is used
+var identities map[string]*Identity
+
+// loadIdentityMapping loads the --identities file, if given, for use by every output
+func loadIdentityMapping() {
+	if identitiesFile == "" {
+		return
+	}
+	var err error
+	identities, err = loadIdentities(identitiesFile)
+	if err != nil {
+		log.Fatalf("Failed to load identities: %v", err)
+	}
+	fmt.Printf("🪪 Loaded %d identities from %s\n", len(identities), identitiesFile)
+}
+
+// loadIdentities reads the identity mapping from a YAML file or a CSV file with the header
+// login,name,email,team,start_date,end_date,bot
+func loadIdentities(filename string) (map[string]*Identity, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read identities file %s: %w", filename, err)
+	}
+	defer file.Close()
+
+	var list []Identity
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".csv":
+		list, err = readIdentitiesCSV(file)
+	default:
+		var config IdentitiesConfig
+		err = yaml.NewDecoder(file).Decode(&config)
+		list = config.Identities
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse identities file %s: %w", filename, err)
+	}
+
+	mapping := make(map[string]*Identity)
+	for i := range list {
+		identity := &list[i]
+		if identity.Login == "" {
+			return nil, fmt.Errorf("identity %d in %s has no login", i+1, filename)
+		}
+		if _, err = parseIdentityDate(identity.StartDate); err != nil {
+			return nil, fmt.Errorf("%s: invalid start_date: %w", identity.Login, err)
+		}
+		if identity.end, err = parseIdentityDate(identity.EndDate); err != nil {
+			return nil, fmt.Errorf("%s: invalid end_date: %w", identity.Login, err)
+		}
+		mapping[strings.ToLower(identity.Login)] = identity
+	}
+	return mapping, nil
+}
+
+// readIdentitiesCSV reads identities from CSV, matching columns by header name
+func readIdentitiesCSV(r io.Reader) ([]Identity, error) {
+	rows, err := csv.NewReader(r).ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, nil
+	}
+
+	columns := make(map[string]int)
+	for i, header := range rows[0] {
+		columns[strings.ToLower(strings.TrimSpace(header))] = i
+	}
+	if _, ok := columns["login"]; !ok {
+		return nil, fmt.Errorf("missing login column")
+	}
+	field := func(row []string, name string) string {
+		if i, ok := columns[name]; ok && i < len(row) {
+			return strings.TrimSpace(row[i])
+		}
+		return ""
+	}
+
+	var list []Identity
+	for _, row := range rows[1:] {
+		identity := Identity{
+			Login:     field(row, "login"),
+			Name:      field(row, "name"),
+			Email:     field(row, "email"),
+			Team:      field(row, "team"),
+			StartDate: field(row, "start_date"),
+			EndDate:   field(row, "end_date"),
+		}
+		if bot := field(row, "bot"); bot != "" {
+			if identity.Bot, err = strconv.ParseBool(bot); err != nil {
+				return nil, fmt.Errorf("%s: invalid bot value %q", identity.Login, bot)
+			}
+		}
+		list = append(list, identity)
+	}
+	return list, nil
+}
+
+// parseIdentityDate parses an optional YYYY-MM-DD date
+func parseIdentityDate(value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	parsed, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return nil, err
+	}
+	return &parsed, nil
+}
+
+// lookupIdentity returns the mapped identity of a login
+func lookupIdentity(login string) (*Identity, bool) {
+	identity, ok := identities[strings.ToLower(login)]
+	return identity, ok
+}
+
+// loginForEmail returns the login mapped to an email address, for sources such as local clones
+// that only know git author names and emails
+func loginForEmail(email string) (string, bool) {
+	if email == "" {
+		return "", false
+	}
+	for _, identity := range identities {
+		if identity.Email != "" && strings.EqualFold(identity.Email, email) {
+			return identity.Login, true
+		}
+	}
+	return "", false
+}
+
+// identityCategory classifies a login as an employee, a bot (mapped or matching a bot pattern) or unmapped
+func identityCategory(login string) string {
+	identity, ok := lookupIdentity(login)
+	switch {
+	case !ok && isBotLogin(login):
+		return identityBot
+	case !ok:
+		return identityUnmapped
+	case identity.Bot:
+		return identityBot
+	default:
+		return identityEmployee
+	}
+}
+
+// personName returns "Name (login)" for mapped logins and the login otherwise
+func personName(login string) string {
+	identity, ok := lookupIdentity(login)
+	if !ok || identity.Name == "" {
+		return login
+	}
+	return fmt.Sprintf("%s (%s)", identity.Name, login)
+}
+
+// personTeam returns the team of a mapped login
+func personTeam(login string) string {
+	if identity, ok := lookupIdentity(login); ok {
+		return identity.Team
+	}
+	return ""
+}
+
+// employmentEndedBefore reports whether a mapped person's employment ended before the given
+// time; the end date is the last day of employment
+func employmentEndedBefore(login string, t time.Time) (*Identity, bool) {
+	identity, ok := lookupIdentity(login)
+	if !ok || identity.Bot || identity.end == nil {
+		return nil, false
+	}
+	return identity, t.After(identity.end.AddDate(0, 0, 1))
+}
+
+// prParticipants returns the author, merger and approvers of a pull request with their roles
+func prParticipants(pr PullRequest) [][2]string {
+	var participants [][2]string
+	if pr.Author.Login != "" {
+		participants = append(participants, [2]string{pr.Author.Login, "author"})
+	}
+	if pr.MergedBy != nil && pr.MergedBy.Login != "" {
+		participants = append(participants, [2]string{pr.MergedBy.Login, "merger"})
+	}
+	for _, login := range approvers(pr) {
+		participants = append(participants, [2]string{login, "approver"})
+	}
+	return participants
+}
+
+// generateIdentitySection writes PR counts by author category and lists unmapped logins
+// and contributions made after employment ended
+func generateIdentitySection(output *os.File, merged []PRRecord) {
+	categoryPRs := make(map[string]int)
+	categoryPeople := make(map[string]map[string]bool)
+	unmapped := make(map[string]map[string]int)
+	var former []string
+
+	for _, item := range merged {
+		category := identityCategory(item.PR.Author.Login)
+		if isBotAuthored(item.PR) {
+			category = identityBot
+		}
+		categoryPRs[category]++
+		if categoryPeople[category] == nil {
+			categoryPeople[category] = make(map[string]bool)
+		}
+		categoryPeople[category][strings.ToLower(item.PR.Author.Login)] = true
+
+		for _, participant := range prParticipants(item.PR) {
+			login, role := participant[0], participant[1]
+			if identityCategory(login) == identityUnmapped {
+				if unmapped[login] == nil {
+					unmapped[login] = make(map[string]int)
+				}
+				unmapped[login][role]++
+			}
+			if identity, ended := employmentEndedBefore(login, *item.PR.MergedAt); ended {
+				former = append(former, fmt.Sprintf("| %s | %s | %s | %s | %s |", markdownLink(fmt.Sprintf("%s#%d", item.Repository, item.PR.Number), item.URL()),
+					personName(login), role, identity.EndDate, item.PR.MergedAt.Format("2006-01-02")))
+			}
+		}
+	}
+
+	fmt.Fprintf(output, "## Contributors\n\n")
+	fmt.Fprintf(output, "| Author Category | Merged PRs | Authors |\n")
+	fmt.Fprintf(output, "|---|---:|---:|\n")
+	for _, category := range []string{identityEmployee, identityBot, identityUnmapped} {
+		fmt.Fprintf(output, "| %s | %d | %d |\n", category, categoryPRs[category], len(categoryPeople[category]))
+	}
+	fmt.Fprintf(output, "\n")
+
+	if len(unmapped) > 0 {
+		fmt.Fprintf(output, "### Unmapped Logins\n\n")
+		fmt.Fprintf(output, "| Login | Authored | Merged | Approved |\n")
+		fmt.Fprintf(output, "|---|---:|---:|---:|\n")
+		for _, login := range sortedKeys(unmapped) {
+			roles := unmapped[login]
+			fmt.Fprintf(output, "| %s | %d | %d | %d |\n", login, roles["author"], roles["merger"], roles["approver"])
+		}
+		fmt.Fprintf(output, "\n")
+	}
+
+	if len(former) > 0 {
+		fmt.Fprintf(output, "### Contributions After Employment Ended\n\n")
+		fmt.Fprintf(output, "| PR | Person | Role | Employment Ended | Merged |\n")
+		fmt.Fprintf(output, "|---|---|---|---|---|\n")
+		for _, line := range former {
+			fmt.Fprintf(output, "%s\n", line)
+		}
+		fmt.Fprintf(output, "\n")
+	}
+
+	fmt.Fprintf(output, "---\n\n")
+}
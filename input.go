@@ -0,0 +1,160 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+)
+
+// provenanceInput marks pull requests read from exported JSON files instead of a provider API
+const provenanceInput = "input"
+
+// inputRecord is one pull request from an exported `gh pr list --json ...` or `gh search prs --json ...` dump
+type inputRecord struct {
+	PullRequest
+	Repository json.RawMessage `json:"repository,omitempty"` // "owner/name" or gh's {"nameWithOwner": "owner/name"}
+}
+
+// loadInputFiles reads exported pull request JSON, JSONL or concatenated JSON files into one
+// result per repository. Each argument is FILE, or OWNER/REPO=FILE for dumps of a single
+// repository whose records carry no repository field or URL.
+func loadInputFiles(args []string, filter *PRFilter) ([]RepositoryResult, error) {
+	results := make(map[string]*RepositoryResult)
+	total := 0
+
+	for _, arg := range args {
+		path, repository := parseInputArg(arg)
+		records, err := readInputRecords(path)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+
+		for _, record := range records {
+			host, repo, err := inputRepository(record, repository)
+			if err != nil {
+				return nil, fmt.Errorf("%s: PR #%d: %w", path, record.Number, err)
+			}
+
+			pr := record.PullRequest
+			if pr.Provenance == "" {
+				pr.Provenance = provenanceInput
+			}
+
+			key := host + "/" + repo
+			if results[key] == nil {
+				results[key] = &RepositoryResult{Repository: repo, Host: host, Provider: providerGitHub}
+			}
+			results[key].PRs = append(results[key].PRs, pr)
+			total++
+		}
+	}
+
+	var loaded []RepositoryResult
+	for _, key := range sortedKeys(results) {
+		result := results[key]
+		result.PRs = filterByPeriod(result.PRs, filter)
+		sort.SliceStable(result.PRs, func(i, j int) bool {
+			return result.PRs[i].Number > result.PRs[j].Number
+		})
+		if filter != nil && filter.Limit > 0 && len(result.PRs) > filter.Limit {
+			result.PRs = result.PRs[:filter.Limit]
+		}
+		loaded = append(loaded, *result)
+	}
+
+	fmt.Printf("📥 Loaded %d pull requests for %d repositories from %d input files\n\n", total, len(loaded), len(args))
+	return loaded, nil
+}
+
+// parseInputArg splits an OWNER/REPO=FILE argument into the file and repository, leaving
+// plain file names (even ones containing "=") untouched
+func parseInputArg(arg string) (string, string) {
+	if name, file, ok := strings.Cut(arg, "="); ok && strings.Contains(name, "/") {
+		if _, err := os.Stat(arg); err != nil {
+			return file, strings.Trim(name, "/")
+		}
+	}
+	return arg, ""
+}
+
+// readInputRecords decodes every pull request in a file, accepting JSON arrays, single
+// objects and newline-delimited or concatenated JSON
+func readInputRecords(path string) ([]inputRecord, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var records []inputRecord
+	decoder := json.NewDecoder(file)
+	for {
+		var value json.RawMessage
+		if err := decoder.Decode(&value); err != nil {
+			if errors.Is(err, io.EOF) {
+				return records, nil
+			}
+			return nil, err
+		}
+
+		if trimmed := strings.TrimSpace(string(value)); strings.HasPrefix(trimmed, "[") {
+			var batch []inputRecord
+			if err := json.Unmarshal(value, &batch); err != nil {
+				return nil, err
+			}
+			records = append(records, batch...)
+			continue
+		}
+
+		var record inputRecord
+		if err := json.Unmarshal(value, &record); err != nil {
+			return nil, err
+		}
+		records = append(records, record)
+	}
+}
+
+// inputRepository determines the host and "owner/name" of an input record from its repository
+// field, the repository given on the command line, or its URL
+func inputRepository(record inputRecord, repository string) (string, string, error) {
+	host := defaultHost
+	var fromURL string
+	if record.URL != "" {
+		// https://host/owner/name/pull/123
+		if parsed, err := url.Parse(record.URL); err == nil && parsed.Host != "" {
+			host = normalizeHost(parsed.Host)
+			if parts := strings.Split(strings.Trim(parsed.Path, "/"), "/"); len(parts) >= 2 {
+				fromURL = parts[0] + "/" + parts[1]
+			}
+		}
+	}
+
+	if len(record.Repository) > 0 && string(record.Repository) != "null" {
+		var name string
+		if err := json.Unmarshal(record.Repository, &name); err != nil {
+			var object struct {
+				NameWithOwner string `json:"nameWithOwner"`
+			}
+			if err := json.Unmarshal(record.Repository, &object); err != nil {
+				return "", "", fmt.Errorf("unrecognized repository field: %s", record.Repository)
+			}
+			name = object.NameWithOwner
+		}
+		if name != "" {
+			return host, name, nil
+		}
+	}
+
+	if repository != "" {
+		return host, repository, nil
+	}
+	if fromURL != "" {
+		return host, fromURL, nil
+	}
+	return "", "", fmt.Errorf("cannot determine the repository; add a repository field or url, or pass OWNER/REPO=FILE")
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestReadInputRecords(t *testing.T) {
+	tests := []struct {
+		name    string
+		arg     string
+		want    []string // host/owner/name#number of each record, in file order
+		wantErr string
+	}{
+		{
+			name: "JSON array with repository fields",
+			arg:  "testdata/input/array.json",
+			want: []string{"github.com/acme/api#12", "github.com/acme/web#11"},
+		},
+		{
+			name: "single object with an object repository",
+			arg:  "testdata/input/wrapper.json",
+			want: []string{"github.com/acme/api#7"},
+		},
+		{
+			name: "JSONL with the repository taken from the URL",
+			arg:  "testdata/input/records.jsonl",
+			want: []string{"github.com/acme/api#3", "github.com/acme/api#2"},
+		},
+		{
+			name: "OWNER/REPO=FILE for records without a repository",
+			arg:  "acme/tools=testdata/input/bare.json",
+			want: []string{"github.com/acme/tools#5"},
+		},
+		{
+			name: "host and repository from an enterprise URL",
+			arg:  "testdata/input/enterprise.json",
+			want: []string{"git.example.com/infra/vault#9"},
+		},
+		{
+			name:    "records without a repository",
+			arg:     "testdata/input/bare.json",
+			wantErr: "cannot determine the repository",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path, repository := parseInputArg(tt.arg)
+			records, err := readInputRecords(path)
+			if err != nil {
+				t.Fatalf("readInputRecords(%q): %v", path, err)
+			}
+
+			var got []string
+			for _, record := range records {
+				host, repo, err := inputRepository(record, repository)
+				if err != nil {
+					if tt.wantErr == "" || !strings.Contains(err.Error(), tt.wantErr) {
+						t.Fatalf("inputRepository(#%d) error = %v, want %q", record.Number, err, tt.wantErr)
+					}
+					return
+				}
+				got = append(got, fmt.Sprintf("%s/%s#%d", host, repo, record.Number))
+			}
+			if tt.wantErr != "" {
+				t.Fatalf("got %v, want error %q", got, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("records = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadInputRecordsRejectsMalformedJSON(t *testing.T) {
+	if _, err := readInputRecords("testdata/input/missing.json"); err == nil {
+		t.Error("readInputRecords of a missing file succeeded")
+	}
+	if _, err := readInputRecords("input_test.go"); err == nil {
+		t.Error("readInputRecords of a Go file succeeded")
+	}
+}
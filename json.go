@@ -0,0 +1,63 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+)
+
+// ReportData is the machine-readable copy of a report, used to compare runs with `diff`
+type ReportData struct {
+	Generated    time.Time  `json:"generated"`
+	StartDate    string     `json:"startDate,omitempty"`
+	EndDate      string     `json:"endDate,omitempty"`
+	PullRequests []PRRecord `json:"pullRequests"`
+}
+
+// outputJSON writes every collected pull request, with its evaluated exceptions, next to the markdown report
+func outputJSON(reportFile string, allPRs []PRRecord) {
+	jsonFile := strings.TrimSuffix(reportFile, ".md") + ".json"
+
+	data, err := json.MarshalIndent(ReportData{
+		Generated:    time.Now().UTC().Truncate(time.Second),
+		StartDate:    startDate,
+		EndDate:      endDate,
+		PullRequests: allPRs,
+	}, "", "  ")
+	if err != nil {
+		log.Printf("Failed to encode JSON report: %v", err)
+		return
+	}
+	if err := os.WriteFile(jsonFile, data, 0644); err != nil {
+		log.Printf("Failed to write JSON report: %v", err)
+		return
+	}
+
+	recordArtifact(jsonFile)
+	fmt.Printf("🧾 JSON report generated: %s\n", jsonFile)
+}
+
+// loadReportData reads a JSON report written by outputJSON, or a bare array of PR records
+func loadReportData(filename string) (*ReportData, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	if strings.HasPrefix(strings.TrimSpace(string(data)), "[") {
+		var records []PRRecord
+		if err := json.Unmarshal(data, &records); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", filename, err)
+		}
+		return &ReportData{PullRequests: records}, nil
+	}
+
+	var report ReportData
+	if err := json.Unmarshal(data, &report); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", filename, err)
+	}
+	return &report, nil
+}
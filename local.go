@@ -0,0 +1,379 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// provenanceLocal marks pull requests reconstructed from a local clone instead of a provider API
+const provenanceLocal = "local"
+
+var (
+	// mergeCommitPattern matches GitHub merge commit subjects
+	mergeCommitPattern = regexp.MustCompile(`^Merge pull request #(\d+) from (\S+)`)
+	// squashCommitPattern matches squash and rebase merge subjects ending in the PR number
+	squashCommitPattern = regexp.MustCompile(`^(.*\S)\s+\(#(\d+)\)$`)
+	// approvalTrailerPattern matches commit trailers that record a reviewer
+	approvalTrailerPattern = regexp.MustCompile(`(?im)^(?:Approved|Reviewed)-by:\s*(.+?)\s*$`)
+)
+
+// localCommitFormat separates commit fields with unit separators and commits with record separators
+const localCommitFormat = "%H%x1f%P%x1f%an%x1f%ae%x1f%aI%x1f%cn%x1f%ce%x1f%cI%x1f%s%x1f%b%x1e"
+
+// LocalGitClient reconstructs merged pull requests from the history of a local git clone
+type LocalGitClient struct{}
+
+// localCommit is a first-parent commit of the audited branch
+type localCommit struct {
+	SHA            string
+	Parents        []string
+	AuthorName     string
+	AuthorEmail    string
+	AuthoredDate   time.Time
+	CommitterName  string
+	CommitterEmail string
+	CommittedDate  time.Time
+	Subject        string
+	Body           string
+}
+
+// NewLocalGitClient creates a client for local git clones
+func NewLocalGitClient() *LocalGitClient {
+	return &LocalGitClient{}
+}
+
+// FetchPullRequests reads the first-parent history of the audited branch and turns merge and
+// squash commits that reference a PR number into pull requests
+func (lc *LocalGitClient) FetchPullRequests(repository Repository, filter *PRFilter, workerConfig *WorkerConfig) ([]PullRequest, error) {
+	if repository.Path == "" {
+		return nil, fmt.Errorf("path is required for local repository %s/%s", repository.Owner, repository.Name)
+	}
+
+	branch := repository.Branch
+	if branch == "" {
+		branch = "HEAD"
+	}
+
+	args := []string{"log", "--first-parent", "--format=" + localCommitFormat}
+	if filter != nil {
+		if filter.StartDate != nil {
+			args = append(args, "--since="+filter.StartDate.Format(time.RFC3339))
+		}
+		if filter.EndDate != nil {
+			args = append(args, "--until="+filter.EndDate.Format(time.RFC3339))
+		}
+	}
+	args = append(args, branch, "--")
+
+	output, err := lc.git(repository.Path, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read history of %s in %s: %w", branch, repository.Path, err)
+	}
+
+	// PRs were merged into the audited branch, named by the checked out branch when not configured
+	baseRef := repository.Branch
+	if baseRef == "" {
+		if head, err := lc.git(repository.Path, "rev-parse", "--abbrev-ref", "HEAD"); err == nil {
+			baseRef = strings.TrimSpace(head)
+		}
+	}
+
+	var prs []PullRequest
+	for _, record := range strings.Split(output, "\x1e") {
+		commit, ok := parseLocalCommit(record)
+		if !ok {
+			continue
+		}
+
+		pr, ok := lc.pullRequestFromCommit(repository.Path, commit)
+		if !ok {
+			continue
+		}
+		pr.BaseRefName = baseRef
+		prs = append(prs, pr)
+
+		if filter != nil && filter.Limit > 0 && len(prs) >= filter.Limit {
+			break
+		}
+	}
+
+	return filterByPeriod(prs, filter), nil
+}
+
+// pullRequestFromCommit reconstructs a pull request from a merge or squash commit
+func (lc *LocalGitClient) pullRequestFromCommit(path string, commit localCommit) (PullRequest, bool) {
+	mergedAt := commit.CommittedDate
+	pr := PullRequest{
+		State:      "MERGED",
+		MergedAt:   &mergedAt,
+		Provenance: provenanceLocal,
+	}
+
+	if match := mergeCommitPattern.FindStringSubmatch(commit.Subject); match != nil && len(commit.Parents) > 1 {
+		pr.Number, _ = strconv.Atoi(match[1])
+
+		// GitHub puts the PR title on the first body line of merge commits
+		pr.Title = strings.TrimSpace(strings.SplitN(commit.Body, "\n", 2)[0])
+		if pr.Title == "" {
+			pr.Title = commit.Subject
+		}
+
+		// The merge commit is authored by whoever merged; the PR author wrote the branch's first commit
+		pr.MergedBy = &Actor{Login: localLogin(commit.AuthorName, commit.AuthorEmail)}
+		pr.Commits = lc.branchCommits(path, commit)
+		pr.CreatedAt = commit.AuthoredDate
+		if len(pr.Commits) > 0 {
+			first := pr.Commits[0].Authors[0]
+			pr.Author.Login = localLogin(first.Name, first.Email)
+			pr.CreatedAt = pr.Commits[0].AuthoredDate
+		}
+	} else if match := squashCommitPattern.FindStringSubmatch(commit.Subject); match != nil {
+		pr.Number, _ = strconv.Atoi(match[2])
+		pr.Title = match[1]
+		pr.Author.Login = localLogin(commit.AuthorName, commit.AuthorEmail)
+		pr.CreatedAt = commit.AuthoredDate
+		pr.Commits = []PRCommit{{
+			Oid:             commit.SHA,
+			MessageHeadline: commit.Subject,
+			MessageBody:     commit.Body,
+			AuthoredDate:    commit.AuthoredDate,
+			CommittedDate:   commit.CommittedDate,
+			Authors:         []CommitAuthor{{Name: commit.AuthorName, Email: commit.AuthorEmail}},
+		}}
+
+		// Squash merges made in the web UI are committed by the platform rather than a person
+		if !strings.HasSuffix(strings.ToLower(commit.CommitterEmail), "noreply@github.com") {
+			pr.MergedBy = &Actor{Login: localLogin(commit.CommitterName, commit.CommitterEmail)}
+		}
+	} else {
+		return PullRequest{}, false
+	}
+
+	// The first parent is the branch before the merge, so the diff against it is the PR's change
+	pr.MergeCommit = &CommitRef{Oid: commit.SHA}
+	if len(commit.Parents) > 0 {
+		pr.Files = lc.changedFiles(path, commit.Parents[0], commit.SHA)
+	}
+	pr.ChangedFiles = len(pr.Files)
+	for _, file := range pr.Files {
+		pr.Additions += file.Additions
+		pr.Deletions += file.Deletions
+	}
+
+	// Reviewers are only known when recorded as commit trailers
+	for _, match := range approvalTrailerPattern.FindAllStringSubmatch(commit.Body, -1) {
+		pr.Reviews = append(pr.Reviews, Review{
+			Author:      Actor{Login: localLogin(trailerIdentity(match[1]))},
+			State:       "APPROVED",
+			SubmittedAt: mergedAt,
+		})
+	}
+
+	return pr, true
+}
+
+// branchCommits returns the commits a merge brought in, oldest first
+func (lc *LocalGitClient) branchCommits(path string, commit localCommit) []PRCommit {
+	output, err := lc.git(path, "log", "--reverse", "--format=%H%x1f%an%x1f%ae%x1f%aI%x1f%cI%x1f%s%x1f%b%x1e", commit.Parents[0]+".."+commit.Parents[1], "--")
+	if err != nil {
+		return nil
+	}
+
+	var commits []PRCommit
+	for _, record := range strings.Split(output, "\x1e") {
+		fields := strings.SplitN(strings.TrimSpace(record), "\x1f", 7)
+		if len(fields) != 7 {
+			continue
+		}
+		authored, _ := time.Parse(time.RFC3339, fields[3])
+		committed, _ := time.Parse(time.RFC3339, fields[4])
+		commits = append(commits, PRCommit{
+			Oid:             fields[0],
+			MessageHeadline: fields[5],
+			MessageBody:     strings.TrimSpace(fields[6]),
+			AuthoredDate:    authored,
+			CommittedDate:   committed,
+			Authors:         []CommitAuthor{{Name: fields[1], Email: fields[2]}},
+		})
+	}
+	return commits
+}
+
+// changedFiles returns the files changed between two commits with their line counts
+func (lc *LocalGitClient) changedFiles(path, from, to string) []ChangedFile {
+	output, err := lc.git(path, "diff", "--numstat", "--no-renames", from, to, "--")
+	if err != nil {
+		return nil
+	}
+
+	var files []ChangedFile
+	for _, line := range strings.Split(strings.TrimSpace(output), "\n") {
+		fields := strings.SplitN(line, "\t", 3)
+		if len(fields) != 3 {
+			continue
+		}
+		// Binary files report "-" for both counts
+		additions, _ := strconv.Atoi(fields[0])
+		deletions, _ := strconv.Atoi(fields[1])
+		files = append(files, ChangedFile{Path: fields[2], Additions: additions, Deletions: deletions})
+	}
+	return files
+}
+
+// ReadFile returns the contents of a file at a commit
+func (lc *LocalGitClient) ReadFile(repository Repository, ref, path string) ([]byte, error) {
+	if _, err := lc.git(repository.Path, "cat-file", "-e", ref+":"+path); err != nil {
+		return nil, fmt.Errorf("%s at %s: %w", path, shortSHA(ref), os.ErrNotExist)
+	}
+	output, err := lc.git(repository.Path, "show", ref+":"+path)
+	if err != nil {
+		return nil, err
+	}
+	return []byte(output), nil
+}
+
+// Releases returns the tags of a local clone created since a time
+func (lc *LocalGitClient) Releases(repository Repository, since time.Time) ([]Release, error) {
+	output, err := lc.git(repository.Path, "for-each-ref", "--format=%(refname:short)%1f%(creatordate:iso-strict)", "refs/tags")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tags in %s: %w", repository.Path, err)
+	}
+
+	var releases []Release
+	for _, line := range strings.Split(strings.TrimSpace(output), "\n") {
+		fields := strings.SplitN(line, "\x1f", 2)
+		if len(fields) != 2 {
+			continue
+		}
+		createdAt, err := time.Parse(time.RFC3339, fields[1])
+		if err != nil || createdAt.Before(since) {
+			continue
+		}
+		releases = append(releases, Release{Tag: fields[0], PublishedAt: createdAt})
+	}
+	return releases, nil
+}
+
+// Deployments returns nothing, as git history does not record deployments
+func (lc *LocalGitClient) Deployments(repository Repository, environment string, since time.Time) ([]Deployment, error) {
+	return nil, nil
+}
+
+// ContainsCommit reports whether a commit is an ancestor of a ref
+func (lc *LocalGitClient) ContainsCommit(repository Repository, ref, sha string) (bool, error) {
+	cmd := exec.Command("git", "-C", repository.Path, "merge-base", "--is-ancestor", sha, ref)
+	if err := cmd.Run(); err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok && exitErr.ExitCode() == 1 {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to compare %s with %s: %w", shortSHA(sha), ref, err)
+	}
+	return true, nil
+}
+
+// localSignatureReasons maps git's %G? signature codes to verification reasons
+var localSignatureReasons = map[string]string{
+	"G": "valid",
+	"U": "valid",
+	"B": "bad_signature",
+	"X": "expired_signature",
+	"Y": "expired_key",
+	"R": "revoked_key",
+	"E": "unknown_key",
+	"N": "unsigned",
+}
+
+// CommitSignatures verifies the signatures of a PR's commits and merge commit with git
+func (lc *LocalGitClient) CommitSignatures(repository Repository, pr PullRequest) (map[string]Signature, error) {
+	args := []string{"log", "--no-walk=unsorted", "--format=%H%x1f%G?%x1f%GS"}
+	for _, commit := range pr.Commits {
+		args = append(args, commit.Oid)
+	}
+	if pr.MergeCommit != nil {
+		args = append(args, pr.MergeCommit.Oid)
+	}
+	if len(args) == 3 {
+		return nil, nil
+	}
+
+	output, err := lc.git(repository.Path, append(args, "--")...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to verify signatures in %s: %w", repository.Path, err)
+	}
+
+	signatures := make(map[string]Signature)
+	for _, line := range strings.Split(strings.TrimSpace(output), "\n") {
+		fields := strings.SplitN(line, "\x1f", 3)
+		if len(fields) != 3 {
+			continue
+		}
+		reason, ok := localSignatureReasons[fields[1]]
+		if !ok {
+			reason = "unknown"
+		}
+		signatures[fields[0]] = Signature{Verified: fields[1] == "G" || fields[1] == "U", Reason: reason, Signer: fields[2]}
+	}
+	return signatures, nil
+}
+
+// git runs a git command in the given repository and returns its output
+func (lc *LocalGitClient) git(path string, args ...string) (string, error) {
+	cmd := exec.Command("git", append([]string{"-C", path}, args...)...)
+	output, err := cmd.Output()
+	if err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok && len(exitErr.Stderr) > 0 {
+			return "", fmt.Errorf("%w: %s", err, strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return "", err
+	}
+	return string(output), nil
+}
+
+// parseLocalCommit parses one record of localCommitFormat output
+func parseLocalCommit(record string) (localCommit, bool) {
+	fields := strings.Split(strings.TrimLeft(record, "\n"), "\x1f")
+	if len(fields) != 10 {
+		return localCommit{}, false
+	}
+
+	authored, _ := time.Parse(time.RFC3339, fields[4])
+	committed, err := time.Parse(time.RFC3339, fields[7])
+	if err != nil {
+		return localCommit{}, false
+	}
+
+	return localCommit{
+		SHA:            fields[0],
+		Parents:        strings.Fields(fields[1]),
+		AuthorName:     fields[2],
+		AuthorEmail:    fields[3],
+		AuthoredDate:   authored,
+		CommitterName:  fields[5],
+		CommitterEmail: fields[6],
+		CommittedDate:  committed,
+		Subject:        fields[8],
+		Body:           strings.TrimSpace(fields[9]),
+	}, true
+}
+
+// trailerIdentity splits a "Name <email>" trailer value into the name and the email address
+func trailerIdentity(value string) (string, string) {
+	if i := strings.Index(value, "<"); i > 0 {
+		return strings.TrimSpace(value[:i]), strings.Trim(strings.TrimSpace(value[i:]), "<>")
+	}
+	return strings.TrimSpace(value), ""
+}
+
+// localLogin identifies a git author by the login their email is mapped to in the identity
+// mapping, falling back to the git author name
+func localLogin(name, email string) string {
+	if login, ok := loginForEmail(email); ok {
+		return login
+	}
+	return name
+}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 	"log"
 	"os"
@@ -8,7 +9,6 @@ import (
 	"time"
 
 	"github.com/spf13/cobra"
-	"github.com/tealeg/xlsx/v3"
 )
 
 var (
@@ -21,6 +21,8 @@ var (
 	pageSize       int
 	batchSize      int
 	batchNumber    int
+	splitOutputBy  string
+	inputFiles     []string
 )
 
 func main() {
@@ -31,15 +33,35 @@ func main() {
 		Run:   run,
 	}
 
-	rootCmd.Flags().StringVarP(&reposFile, "repos", "r", "repositories.yaml", "Path to repositories configuration file")
-	rootCmd.Flags().StringVarP(&startDate, "start", "s", "", "Start date for filtering PRs by merge date (YYYY-MM-DD format)")
-	rootCmd.Flags().StringVarP(&endDate, "end", "e", "", "End date for filtering PRs by merge date (YYYY-MM-DD format)")
+	rootCmd.PersistentFlags().StringVarP(&reposFile, "repos", "r", "repositories.yaml", "Path to repositories configuration file")
+	rootCmd.PersistentFlags().StringVarP(&startDate, "start", "s", "", "Start date for filtering PRs by merge date (YYYY-MM-DD format)")
+	rootCmd.PersistentFlags().StringVarP(&endDate, "end", "e", "", "End date for filtering PRs by merge date (YYYY-MM-DD format)")
 	rootCmd.Flags().StringVarP(&outputFile, "output", "o", "pr-analysis.md", "Output markdown file to write results (default: pr-analysis.md)")
-	rootCmd.Flags().IntVarP(&maxWorkers, "workers", "w", 10, "Maximum number of concurrent workers (default: 10 for large datasets)")
-	rootCmd.Flags().IntVarP(&maxPRsPerRepo, "max-prs", "m", 0, "Maximum PRs to fetch per repository (0 = no limit)")
-	rootCmd.Flags().IntVarP(&pageSize, "page-size", "p", 200, "Number of PRs per page for pagination (default: 200 for large datasets)")
-	rootCmd.Flags().IntVarP(&batchSize, "batch-size", "b", 0, "Process repositories in batches (0 = process all at once)")
-	rootCmd.Flags().IntVarP(&batchNumber, "batch", "n", 1, "Batch number to process (used with --batch-size)")
+	rootCmd.PersistentFlags().IntVarP(&maxWorkers, "workers", "w", 10, "Maximum number of concurrent workers (default: 10 for large datasets)")
+	rootCmd.PersistentFlags().IntVarP(&maxPRsPerRepo, "max-prs", "m", 0, "Maximum PRs to fetch per repository (0 = no limit)")
+	rootCmd.PersistentFlags().IntVarP(&pageSize, "page-size", "p", 200, "Number of PRs per page for pagination (default: 200 for large datasets)")
+	rootCmd.PersistentFlags().IntVarP(&batchSize, "batch-size", "b", 0, "Process repositories in batches (0 = process all at once)")
+	rootCmd.PersistentFlags().IntVarP(&batchNumber, "batch", "n", 1, "Batch number to process (used with --batch-size)")
+	rootCmd.PersistentFlags().BoolVar(&excludeBots, "exclude-bots", false, "Leave bot-authored PRs out of the population instead of listing them as automated changes")
+	rootCmd.PersistentFlags().StringVar(&identitiesFile, "identities", "", "Identity mapping file (YAML or CSV) of login to name, email, team and employment dates")
+	rootCmd.PersistentFlags().StringSliceVar(&prStates, "states", nil, "PR states to report: merged, closed, open or all (default: merged; repeatable or comma-separated)")
+	rootCmd.PersistentFlags().BoolVar(&checkCodeOwners, "codeowners", false, "Verify that each PR was approved by an owner of its changed files per CODEOWNERS at the merge commit")
+	rootCmd.PersistentFlags().BoolVar(&checkSignatures, "signatures", false, "Verify the signatures of each merged PR's commits and merge commit")
+	rootCmd.PersistentFlags().BoolVar(&trackDeployments, "deployments", false, "Find the first production deployment and release that include each merged PR")
+	rootCmd.PersistentFlags().StringVar(&ownerTeamsFile, "owner-teams", "", "YAML mapping of CODEOWNERS teams to member logins (default: resolve teams through the GitHub API)")
+	rootCmd.PersistentFlags().StringSliceVar(&authorFilter, "author", nil, "Only include PRs authored by these logins (repeatable or comma-separated)")
+	rootCmd.PersistentFlags().StringSliceVar(&reviewerFilter, "reviewer", nil, "Only include PRs approved by these logins (repeatable or comma-separated)")
+	rootCmd.PersistentFlags().StringSliceVar(&mergedByFilter, "merged-by", nil, "Only include PRs merged by these logins (repeatable or comma-separated)")
+	rootCmd.PersistentFlags().StringSliceVar(&teamFilter, "team", nil, "Only include PRs authored, approved or merged by members of these teams (identity mapping team or org/team)")
+	rootCmd.PersistentFlags().StringSliceVarP(&inputFiles, "input", "i", nil, "Read pull requests from exported gh JSON/JSONL files instead of fetching (FILE or OWNER/REPO=FILE, repeatable)")
+
+	rootCmd.Flags().StringVar(&splitOutputBy, "split-output-by", "", "Also write one set of reports per group (supported: vertical)")
+	rootCmd.Flags().StringVar(&signKeyFile, "sign-key", "", "Sign the report manifest with an ed25519 private key file (PEM or base64/hex)")
+
+	rootCmd.AddCommand(newSampleCommand())
+	rootCmd.AddCommand(newEvidenceCommand())
+	rootCmd.AddCommand(newVerifyCommand())
+	rootCmd.AddCommand(newDiffCommand())
 
 	if err := rootCmd.Execute(); err != nil {
 		log.Fatal(err)
@@ -47,25 +69,189 @@ func main() {
 }
 
 func run(cmd *cobra.Command, args []string) {
+	if splitOutputBy != "" && splitOutputBy != "vertical" {
+		log.Fatalf("Invalid --split-output-by value %q (expected vertical)", splitOutputBy)
+	}
+
+	config, allPRs := collectPullRequests()
+
+	// Output results
+	outputResults(outputFile, allPRs)
+	
+	// Output XLSX
+	outputXLSX(outputFile, allPRs)
+
+	// Output HTML dashboard
+	outputHTML(outputFile, allPRs)
+
+	// Output JSON for comparing runs with `diff`
+	outputJSON(outputFile, allPRs)
+
+	// One set of reports per vertical for distribution to business owners
+	if splitOutputBy == "vertical" {
+		outputPerVertical(allPRs)
+	}
+
+	// Record hashes of everything written so the reports can be verified later
+	manifestFile := strings.TrimSuffix(outputFile, ".md") + ".manifest.json"
+	if err := writeManifest(manifestFile, NewGitHubClient(), configuredHosts(config)); err != nil {
+		log.Fatalf("Failed to write manifest: %v", err)
+	}
+}
+
+// collectPullRequests loads the repository configuration and fetches the pull
+// request population for every configured repository
+func collectPullRequests() (*RepositoriesConfig, []PRRecord) {
 	// Load repositories configuration
 	config, err := LoadRepositories(reposFile)
 	if err != nil {
-		log.Fatalf("Failed to load repositories: %v", err)
+		// Reports built from input files only use the configuration for verticals
+		if len(inputFiles) == 0 || !errors.Is(err, os.ErrNotExist) {
+			log.Fatalf("Failed to load repositories: %v", err)
+		}
+		config = &RepositoriesConfig{}
+	}
+
+	// Load the identity mapping used to name people and check employment dates
+	loadIdentityMapping()
+	botPatterns = compileBotPatterns(config.Bots)
+	changeCategories, err = compileChangeTypes(config.ChangeTypes)
+	if err != nil {
+		log.Fatalf("Failed to load change types: %v", err)
+	}
+	sensitiveApprovals = defaultSensitiveApprovals
+	if config.SensitiveApprovals > 0 {
+		sensitiveApprovals = config.SensitiveApprovals
+	}
+	oversizedLines, oversizedFiles = defaultOversizedLines, config.OversizedFiles
+	if config.OversizedLines > 0 {
+		oversizedLines = config.OversizedLines
+	}
+	signedBranches = config.SignedBranches
+	productionEnvironment = defaultProductionEnvironment
+	if config.ProductionEnvironment != "" {
+		productionEnvironment = config.ProductionEnvironment
+	}
+
+	// Parse date filters
+	filter, err := parseDateFilter(configuredHosts(config))
+	if err != nil {
+		log.Fatalf("Failed to parse filters: %v", err)
 	}
+	peopleFilter = filter
+	printPeopleScope(filter)
 
+	var results []RepositoryResult
+	if len(inputFiles) > 0 {
+		results, err = loadInputFiles(inputFiles, filter)
+		if err != nil {
+			log.Fatalf("Failed to load input files: %v", err)
+		}
+	} else {
+		results = fetchRepositoryResults(config, filter)
+	}
+
+	// Process results
+	var allPRs []PRRecord
+
+	successCount := 0
+	errorCount := 0
+
+	for _, result := range results {
+		if result.Error != nil {
+			log.Printf("❌ Warning: Failed to fetch PRs for %s: %v", result.Repository, result.Error)
+			errorCount++
+			continue
+		}
+
+		successCount++
+		fmt.Printf("✅ %s: Found %d pull requests\n", result.Repository, len(result.PRs))
+
+		// Find the verticals for this repository
+		verticals := findVerticalsForRepository(result.Host, result.Repository, config)
+
+		// Add repository info to each PR
+		for _, pr := range result.PRs {
+			if pr.URL == "" && result.Provider != providerLocal {
+				pr.URL = pullRequestURL(result.Host, result.Repository, pr.Number)
+			}
+			allPRs = append(allPRs, PRRecord{
+				Repository: result.Repository,
+				Host:       result.Host,
+				Provider:   result.Provider,
+				Verticals:  verticals,
+				PR:         pr,
+			})
+		}
+	}
+
+	fmt.Printf("\n🎉 Large Dataset Processing Complete!\n")
+	fmt.Printf("📊 Results: %d repositories processed successfully, %d failed\n", successCount, errorCount)
+	fmt.Printf("📈 Total PRs collected: %d\n", len(allPRs))
+
+	// Keep only the PRs of the people in scope, including from sources that could not filter by person
+	if hasPeopleScope(filter) {
+		allPRs = filterByPeople(allPRs, filter)
+		fmt.Printf("👤 %d PRs in scope\n", len(allPRs))
+	}
+
+	// Drop automated changes from the population when asked to
+	if excludeBots {
+		human, automated := splitBotRecords(allPRs)
+		allPRs = human
+		fmt.Printf("🤖 Excluded %d bot-authored PRs\n", len(automated))
+	}
+
+	// Find the code owners of every PR's changed files
+	if checkCodeOwners {
+		loadOwnerTeams()
+		resolveCodeOwners(config, allPRs)
+	}
+
+	// Check that merged commits carry verified signatures
+	if checkSignatures {
+		verifySignatures(config, allPRs)
+	}
+
+	// Find when each merged change reached production
+	if trackDeployments {
+		correlateDeployments(config, allPRs)
+	}
+
+	// Derive PR sizes from the changed files when the provider did not report them
+	fillChangeVolume(allPRs)
+
+	// Tag changes to sensitive paths for the stricter high-risk controls
+	tagSensitiveChanges(config, allPRs)
+
+	// Classify changes so emergency changes get their retrospective control
+	classifyChanges(allPRs)
+
+	// Link reverts to the changes they rolled back
+	detectReverts(allPRs)
+
+	// Evaluate audit controls so every output can highlight exceptions
+	applyControls(allPRs)
+
+	return config, allPRs
+}
+
+// fetchRepositoryResults fetches the pull requests of every configured repository (or the
+// selected batch) from its provider
+func fetchRepositoryResults(config *RepositoriesConfig, filter *PRFilter) []RepositoryResult {
 	// Collect all repositories (from both direct list and verticals) and deduplicate
 	repositoryMap := make(map[string]Repository)
 	
 	// Add direct repositories
 	for _, repo := range config.Repositories {
-		key := fmt.Sprintf("%s/%s", repo.Owner, repo.Name)
+		key := fmt.Sprintf("%s/%s/%s", repo.Host, repo.Owner, repo.Name)
 		repositoryMap[key] = repo
 	}
 	
 	// Add repositories from verticals
 	for _, vertical := range config.Verticals {
 		for _, repo := range vertical.Repositories {
-			key := fmt.Sprintf("%s/%s", repo.Owner, repo.Name)
+			key := fmt.Sprintf("%s/%s/%s", repo.Host, repo.Owner, repo.Name)
 			repositoryMap[key] = repo
 		}
 	}
@@ -85,12 +271,6 @@ func run(cmd *cobra.Command, args []string) {
 		}
 	}
 
-	// Parse date filters
-	filter, err := parseDateFilter()
-	if err != nil {
-		log.Fatalf("Failed to parse date filter: %v", err)
-	}
-
 	// Create worker configuration
 	workerConfig := &WorkerConfig{
 		MaxWorkers:    maxWorkers,
@@ -98,12 +278,22 @@ func run(cmd *cobra.Command, args []string) {
 		PageSize:      pageSize,
 	}
 
-	// Initialize GitHub client
-	githubClient := NewGitHubClient()
+	// Initialize the provider clients
+	providers := NewProviders()
 
-	// Check if GitHub CLI is available and authenticated
-	if err := githubClient.CheckGitHubCLI(); err != nil {
-		log.Fatalf("GitHub CLI check failed: %v", err)
+	// Check if GitHub CLI is available and authenticated to every GitHub host being processed
+	hostSet := make(map[string]bool)
+	var hosts []string
+	for _, repo := range repositoriesToProcess {
+		if repo.Provider == providerGitHub && !hostSet[repo.Host] {
+			hostSet[repo.Host] = true
+			hosts = append(hosts, repo.Host)
+		}
+	}
+	if len(hosts) > 0 {
+		if err := NewGitHubClient().CheckGitHubCLI(hosts); err != nil {
+			log.Fatalf("GitHub CLI check failed: %v", err)
+		}
 	}
 
 	if batchSize > 0 {
@@ -130,61 +320,23 @@ func run(cmd *cobra.Command, args []string) {
 	fmt.Println()
 
 	// Fetch pull requests concurrently
-	results := githubClient.FetchPullRequestsConcurrent(repositoriesToProcess, filter, workerConfig)
-
-	// Process results
-	var allPRs []struct {
-		Repository string
-		Verticals  []string
-		PR         PullRequest
-	}
-
-	successCount := 0
-	errorCount := 0
-
-	for _, result := range results {
-		if result.Error != nil {
-			log.Printf("❌ Warning: Failed to fetch PRs for %s: %v", result.Repository, result.Error)
-			errorCount++
-			continue
-		}
-
-		successCount++
-		fmt.Printf("✅ %s: Found %d pull requests\n", result.Repository, len(result.PRs))
-
-		// Find the verticals for this repository
-		verticals := findVerticalsForRepository(result.Repository, config)
-
-		// Add repository info to each PR
-		for _, pr := range result.PRs {
-			allPRs = append(allPRs, struct {
-				Repository string
-				Verticals  []string
-				PR         PullRequest
-			}{
-				Repository: result.Repository,
-				Verticals:  verticals,
-				PR:         pr,
-			})
-		}
-	}
-
-	fmt.Printf("\n🎉 Large Dataset Processing Complete!\n")
-	fmt.Printf("📊 Results: %d repositories processed successfully, %d failed\n", successCount, errorCount)
-	fmt.Printf("📈 Total PRs collected: %d\n", len(allPRs))
-
-	// Output results
-	outputResults(allPRs)
-	
-	// Output XLSX
-	outputXLSX(allPRs)
+	return providers.FetchPullRequestsConcurrent(repositoriesToProcess, filter, workerConfig)
 }
 
-func parseDateFilter() (*PRFilter, error) {
+func parseDateFilter(hosts []string) (*PRFilter, error) {
 	var filter *PRFilter
 
-	if startDate != "" || endDate != "" || maxPRsPerRepo > 0 {
-		filter = &PRFilter{}
+	states, err := parseStates(prStates)
+	if err != nil {
+		return nil, err
+	}
+
+	scoped := len(authorFilter) > 0 || len(reviewerFilter) > 0 || len(mergedByFilter) > 0 || len(teamFilter) > 0
+	if startDate != "" || endDate != "" || maxPRsPerRepo > 0 || len(prStates) > 0 || scoped {
+		filter = &PRFilter{States: states}
+		if err := applyPeopleScope(filter, hosts); err != nil {
+			return nil, err
+		}
 
 		if startDate != "" {
 			parsed, err := time.Parse("2006-01-02", startDate)
@@ -227,11 +379,11 @@ func getBatchRepositories(repositories []Repository, batchSize, batchNumber int)
 }
 
 // findVerticalsForRepository finds which verticals a repository belongs to
-func findVerticalsForRepository(repoName string, config *RepositoriesConfig) []string {
+func findVerticalsForRepository(host, repoName string, config *RepositoriesConfig) []string {
 	var verticals []string
 	for _, vertical := range config.Verticals {
 		for _, repo := range vertical.Repositories {
-			if fmt.Sprintf("%s/%s", repo.Owner, repo.Name) == repoName {
+			if repo.Host == host && fmt.Sprintf("%s/%s", repo.Owner, repo.Name) == repoName {
 				verticals = append(verticals, vertical.Name)
 			}
 		}
@@ -239,20 +391,48 @@ func findVerticalsForRepository(repoName string, config *RepositoriesConfig) []s
 	return verticals
 }
 
-func outputResults(allPRs []struct {
-	Repository string
-	Verticals  []string
-	PR         PullRequest
-}) {
+// pullRequestURL builds the web URL of a pull request on the given host
+func pullRequestURL(host, repo string, number int) string {
+	return fmt.Sprintf("https://%s/%s/pull/%d", normalizeHost(host), repo, number)
+}
+
+// RepositoryKey identifies the record's repository across hosts: owner/name on github.com and
+// host/owner/name elsewhere, so same-named repositories on different hosts stay apart
+func (r PRRecord) RepositoryKey() string {
+	if host := normalizeHost(r.Host); host != defaultHost {
+		return host + "/" + r.Repository
+	}
+	return r.Repository
+}
+
+// URL returns the web URL of the record's pull request, or an empty string for PRs
+// reconstructed from a local clone, which have no web page
+func (r PRRecord) URL() string {
+	if r.PR.URL != "" || r.Provider == providerLocal {
+		return r.PR.URL
+	}
+	return pullRequestURL(r.Host, r.Repository, r.PR.Number)
+}
+
+// markdownLink returns a markdown link, or the plain text when there is no URL
+func markdownLink(text, url string) string {
+	if url == "" {
+		return text
+	}
+	return fmt.Sprintf("[%s](%s)", text, url)
+}
+
+func outputResults(reportFile string, allPRs []PRRecord) {
 	var output *os.File
 	var err error
 
 	// Always create a markdown file
-	output, err = os.Create(outputFile)
+	output, err = os.Create(reportFile)
 	if err != nil {
 		log.Fatalf("Failed to create output file: %v", err)
 	}
 	defer output.Close()
+	recordArtifact(reportFile)
 
 	// Generate markdown header
 	generateMarkdownHeader(output, allPRs)
@@ -262,27 +442,74 @@ func outputResults(allPRs []struct {
 		return
 	}
 
-	// Group PRs by repository - only include merged PRs
-	repoPRs := make(map[string][]PullRequest)
+	// Only include PRs that are actually merged (have a merge date)
+	var merged []PRRecord
 	for _, item := range allPRs {
-		// Only include PRs that are actually merged (have a merge date)
 		if item.PR.MergedAt != nil && item.PR.State == "MERGED" {
-			repoPRs[item.Repository] = append(repoPRs[item.Repository], item.PR)
+			merged = append(merged, item)
 		}
 	}
 
-	// Generate repository sections
-	for repo, prs := range repoPRs {
-		generateRepositorySection(output, repo, prs)
+	// Contributor categories when an identity mapping is used
+	if identities != nil {
+		generateIdentitySection(output, merged)
+	}
+
+	// Scoped audits list each person's PRs by role across every repository
+	if hasPeopleScope(peopleFilter) {
+		generatePeopleSections(output, merged, peopleFilter)
 	}
 
+	// Changes to sensitive paths are listed together before the per-repository detail
+	if highRisk, unverified := highRiskRecords(merged), sensitiveUnverifiedRecords(merged); len(highRisk) > 0 || len(unverified) > 0 {
+		generateHighRiskSection(output, highRisk, unverified)
+	}
+
+	// Bot-authored PRs are listed in their own section after the human changes
+	human, automated := splitBotRecords(merged)
+
+	// Group by vertical when the configuration defines verticals
+	if hasVerticals(merged) {
+		generateVerticalRollup(output, merged)
+		generateVerticalSections(output, human)
+	} else {
+		// Group PRs by repository
+		repoPRs := make(map[string][]PRRecord)
+		for _, item := range human {
+			repoPRs[item.RepositoryKey()] = append(repoPRs[item.RepositoryKey()], item)
+		}
+
+		// Generate repository sections
+		for _, repo := range sortedKeys(repoPRs) {
+			generateRepositorySection(output, "##", repo, repoPRs[repo])
+		}
+	}
+
+	if len(automated) > 0 {
+		generateAutomatedSection(output, automated)
+	}
+
+	// Changes that never reached production
+	if never := neverDeployedRecords(merged); len(never) > 0 {
+		generateNeverDeployedSection(output, never)
+	}
+
+	// Rolled back changes, which feed the change failure rate
+	if len(revertRecords(merged)) > 0 {
+		generateRevertSection(output, merged)
+	}
+
+	generateMetricsSection(output, merged)
+
+	// Closed-unmerged and open PRs, when requested, are separate populations
+	for _, section := range stateSections {
+		if records := recordsInState(allPRs, section.State); len(records) > 0 {
+			generateStateSection(output, section.Icon+" "+section.Name, records)
+		}
+	}
 }
 
-func generateMarkdownHeader(output *os.File, allPRs []struct {
-	Repository string
-	Verticals  []string
-	PR         PullRequest
-}) {
+func generateMarkdownHeader(output *os.File, allPRs []PRRecord) {
 	fmt.Fprintf(output, "# Merged Pull Request Analysis Report\n\n")
 	
 	// Generate timestamp
@@ -291,27 +518,39 @@ func generateMarkdownHeader(output *os.File, allPRs []struct {
 	// Generate summary statistics - only count merged PRs
 	repoCount := make(map[string]bool)
 	mergedCount := 0
+	botCount := 0
 	
 	for _, item := range allPRs {
 		if item.PR.MergedAt != nil {
-			repoCount[item.Repository] = true
+			repoCount[item.RepositoryKey()] = true
 			mergedCount++
+			if isBotAuthored(item.PR) {
+				botCount++
+			}
 		}
 	}
 	
 	fmt.Fprintf(output, "## Summary\n\n")
 	fmt.Fprintf(output, "- **Total Repositories with Merged PRs:** %d\n", len(repoCount))
 	fmt.Fprintf(output, "- **Total Merged Pull Requests:** %d\n", mergedCount)
+	if botCount > 0 {
+		fmt.Fprintf(output, "- **Automated (Bot-Authored) Pull Requests:** %d\n", botCount)
+	}
+	for _, section := range stateSections {
+		if count := len(recordsInState(allPRs, section.State)); count > 0 {
+			fmt.Fprintf(output, "- **%s:** %d\n", section.Name, count)
+		}
+	}
 	
 	fmt.Fprintf(output, "\n---\n\n")
 }
 
-func generateRepositorySection(output *os.File, repo string, prs []PullRequest) {
+func generateRepositorySection(output *os.File, heading string, repo string, records []PRRecord) {
 	// Filter for only merged PRs
-	var mergedPRs []PullRequest
-	for _, pr := range prs {
-		if pr.MergedAt != nil {
-			mergedPRs = append(mergedPRs, pr)
+	var mergedPRs []PRRecord
+	for _, item := range records {
+		if item.PR.MergedAt != nil {
+			mergedPRs = append(mergedPRs, item)
 		}
 	}
 	
@@ -321,135 +560,91 @@ func generateRepositorySection(output *os.File, repo string, prs []PullRequest)
 	}
 	
 	// Repository header
-	fmt.Fprintf(output, "## %s\n\n", repo)
+	fmt.Fprintf(output, "%s %s\n\n", heading, repo)
 	
 	// Sort PRs by number (descending)
 	for i := 0; i < len(mergedPRs)-1; i++ {
 		for j := i + 1; j < len(mergedPRs); j++ {
-			if mergedPRs[i].Number < mergedPRs[j].Number {
+			if mergedPRs[i].PR.Number < mergedPRs[j].PR.Number {
 				mergedPRs[i], mergedPRs[j] = mergedPRs[j], mergedPRs[i]
 			}
 		}
 	}
 	
 	// Generate PR list
-	for _, pr := range mergedPRs {
-		generatePRMarkdown(output, repo, pr)
+	for _, item := range mergedPRs {
+		generatePRMarkdown(output, item)
 	}
 	
 	fmt.Fprintf(output, "\n---\n\n")
 }
 
-func generatePRMarkdown(output *os.File, repo string, pr PullRequest) {
-	// Create GitHub PR URL
-	prURL := fmt.Sprintf("https://github.com/%s/pull/%d", repo, pr.Number)
+func generatePRMarkdown(output *os.File, item PRRecord) {
+	pr := item.PR
+
+	// Use the URL reported by the host, falling back to one built from the record's host
+	prURL := item.URL()
 	
 	// PR number with embedded URL
-	fmt.Fprintf(output, "[#%d](%s)", pr.Number, prURL)
+	fmt.Fprintf(output, "%s", markdownLink(fmt.Sprintf("#%d", pr.Number), prURL))
 	
 	// Author
 	if pr.Author.Login != "" {
-		fmt.Fprintf(output, " by **%s**", pr.Author.Login)
+		fmt.Fprintf(output, " by **%s**", personName(pr.Author.Login))
 	}
 	
 	// Merge date (we know it's merged since we filtered for it)
 	fmt.Fprintf(output, " - merged %s", pr.MergedAt.Format("2006-01-02"))
-	
-	fmt.Fprintf(output, "\n")
-}
 
-func outputXLSX(allPRs []struct {
-	Repository string
-	Verticals  []string
-	PR         PullRequest
-}) {
-	// Create XLSX filename based on output file
-	xlsxFile := strings.TrimSuffix(outputFile, ".md") + ".xlsx"
-	
-	// Create a new Excel file
-	file := xlsx.NewFile()
-	
-	// Group PRs by repository with vertical info - only include merged PRs
-	repoPRs := make(map[string]struct {
-		Verticals []string
-		PRs       []PullRequest
-	})
-	for _, item := range allPRs {
-		// Only include PRs that are actually merged (have a merge date)
-		if item.PR.MergedAt != nil && item.PR.State == "MERGED" {
-			repoData := repoPRs[item.Repository]
-			repoData.Verticals = item.Verticals
-			repoData.PRs = append(repoData.PRs, item.PR)
-			repoPRs[item.Repository] = repoData
-		}
+	// Change volume
+	if changeVolumeKnown(pr) {
+		fmt.Fprintf(output, " (%s)", changeVolume(pr))
 	}
-	
-	// Create a worksheet for each repository
-	for repoName, repoData := range repoPRs {
-		// Extract just the repository name (remove organization prefix)
-		repoNameOnly := repoName
-		if strings.Contains(repoName, "/") {
-			parts := strings.Split(repoName, "/")
-			repoNameOnly = parts[len(parts)-1] // Get the last part (repository name)
-		}
-		
-		// Create worksheet name with vertical prefix
-		var sheetName string
-		if len(repoData.Verticals) > 0 {
-			// Join multiple verticals with "/"
-			verticalsStr := strings.Join(repoData.Verticals, "/")
-			sheetName = fmt.Sprintf("%s - %s", verticalsStr, repoNameOnly)
-		} else {
-			sheetName = repoNameOnly
-		}
-		
-		// Clean worksheet name for Excel (Excel has restrictions)
-		sheetName = strings.ReplaceAll(sheetName, "/", "-")
-		if len(sheetName) > 31 { // Excel worksheet name limit
-			sheetName = sheetName[:31]
-		}
-		
-		sheet, err := file.AddSheet(sheetName)
-		if err != nil {
-			log.Printf("Failed to create Excel sheet for %s: %v", repoName, err)
-			continue
-		}
-		
-		// Create header row
-		headerRow := sheet.AddRow()
-		headerRow.AddCell().SetString("PR_Number")
-		headerRow.AddCell().SetString("Author")
-		headerRow.AddCell().SetString("Merge_Date")
-		
-		// Add data rows
-		for _, pr := range repoData.PRs {
-			prURL := fmt.Sprintf("https://github.com/%s/pull/%d", repoName, pr.Number)
-			author := pr.Author.Login
-			if author == "" {
-				author = "Unknown"
-			}
-			
-			// Create row
-			row := sheet.AddRow()
-			
-			// Create hyperlink cell for PR number
-			prCell := row.AddCell()
-			prCell.SetString(fmt.Sprintf("#%d", pr.Number))
-			prCell.SetHyperlink(prURL, fmt.Sprintf("#%d", pr.Number), "")
-			
-			row.AddCell().SetString(author)
-			row.AddCell().SetString(pr.MergedAt.Format("2006-01-02"))
-		}
+
+	// Change category, when not a standard change
+	if pr.ChangeType != "" && pr.ChangeType != changeTypeStandard {
+		fmt.Fprintf(output, " - 🏷️ %s", pr.ChangeType)
 	}
-	
-	// Save the file
-	err := file.Save(xlsxFile)
-	if err != nil {
-		log.Printf("Failed to save Excel file: %v", err)
-		return
+
+	// PRs too large to review reliably
+	if isOversized(pr) {
+		fmt.Fprintf(output, " - 🐘 oversized")
+	}
+
+	// Changes to sensitive paths
+	if isHighRisk(pr) {
+		fmt.Fprintf(output, " - 🔥 high-risk")
+	}
+	if pr.SensitiveUnverified {
+		fmt.Fprintf(output, " - ❓ sensitive paths unverified")
+	}
+
+	// Reverts and reverted changes
+	if len(pr.Reverts) > 0 {
+		fmt.Fprintf(output, " - ⏪ reverts %s", prNumbers(pr.Reverts))
+	} else if pr.IsRevert {
+		fmt.Fprintf(output, " - ⏪ revert")
+	}
+	if isReverted(pr) {
+		fmt.Fprintf(output, " - ⏪ reverted by %s", prNumbers(pr.RevertedBy))
+	}
+
+	// First production deployment or release
+	if pr.DeployedAt != nil {
+		fmt.Fprintf(output, " - 🚀 deployed %s", pr.DeployedAt.Format("2006-01-02"))
+	} else if pr.ReleasedIn != "" {
+		fmt.Fprintf(output, " - 🚀 released in %s", pr.ReleasedIn)
 	}
-	
-	fmt.Printf("📊 Excel report generated: %s\n", xlsxFile)
-}
 
+	// Records not read from a provider API
+	if pr.Provenance != "" {
+		fmt.Fprintf(output, " - 📂 %s", pr.Provenance)
+	}
 
+	// Control exceptions
+	if len(pr.Exceptions) > 0 {
+		fmt.Fprintf(output, " - ⚠️ %s", exceptionTypes(pr))
+	}
+	
+	fmt.Fprintf(output, "\n")
+}
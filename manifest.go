@@ -0,0 +1,353 @@
+package main
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// version is the tool version recorded in report manifests (set with -ldflags "-X main.version=...")
+var version = "dev"
+
+var (
+	signKeyFile   string
+	publicKeyFile string
+)
+
+// generatedArtifacts collects the report files written during a run
+var generatedArtifacts []string
+
+// ReportManifest records how a set of report artifacts was generated so they can
+// later be checked for modification
+type ReportManifest struct {
+	Tool           string             `json:"tool"`
+	ToolVersion    string             `json:"toolVersion"`
+	Generated      time.Time          `json:"generated"`
+	Arguments      []string           `json:"arguments"`
+	ConfigFile     string             `json:"configFile,omitempty"`
+	ConfigSHA256   string             `json:"configSha256,omitempty"`
+	Inputs         []ManifestArtifact `json:"inputs,omitempty"` // Exported PR data used instead of fetching (--input)
+	GitHubIdentity string             `json:"githubIdentity"`
+	Artifacts      []ManifestArtifact `json:"artifacts"`
+	PublicKey      string             `json:"publicKey,omitempty"` // Base64 ed25519 public key of the signer
+	Signature      string             `json:"signature,omitempty"` // Base64 ed25519 signature over the manifest without this field
+}
+
+// ManifestArtifact describes a single report file, relative to the manifest's directory
+type ManifestArtifact struct {
+	Path   string `json:"path"`
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256"`
+}
+
+// recordArtifact registers a generated report file for inclusion in the manifest
+func recordArtifact(path string) {
+	generatedArtifacts = append(generatedArtifacts, path)
+}
+
+func newVerifyCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "verify <manifest>",
+		Short: "Verify report artifacts against a manifest",
+		Long:  "Checks the SHA-256 hashes of every artifact listed in a report manifest and, when signed, the manifest's ed25519 signature",
+		Args:  cobra.ExactArgs(1),
+		Run:   runVerify,
+	}
+
+	cmd.Flags().StringVar(&publicKeyFile, "public-key", "", "Trusted ed25519 public key file (PEM or base64/hex) to verify the signature against")
+
+	return cmd
+}
+
+// writeManifest hashes every generated artifact and writes the (optionally signed) manifest
+func writeManifest(manifestFile string, githubClient *GitHubClient, hosts []string) error {
+	manifest := ReportManifest{
+		Tool:        "audit-ask",
+		ToolVersion: version,
+		Generated:   time.Now().UTC().Truncate(time.Second),
+		Arguments:   os.Args[1:],
+	}
+
+	configHash, _, err := hashFile(reposFile)
+	switch {
+	case err == nil:
+		manifest.ConfigFile = reposFile
+		manifest.ConfigSHA256 = configHash
+	case len(inputFiles) > 0 && errors.Is(err, os.ErrNotExist):
+		// Reports built from input files need no configuration
+	default:
+		return fmt.Errorf("failed to hash configuration file: %w", err)
+	}
+
+	// Input files are recorded so the exported data the reports were built from can be checked too
+	for _, arg := range inputFiles {
+		path, _ := parseInputArg(arg)
+		sum, size, err := hashFile(path)
+		if err != nil {
+			return fmt.Errorf("failed to hash input file %s: %w", path, err)
+		}
+		manifest.Inputs = append(manifest.Inputs, ManifestArtifact{Path: path, Size: size, SHA256: sum})
+	}
+
+	var identities []string
+	for _, host := range hosts {
+		identity, err := githubClient.CurrentUser(host)
+		if err != nil {
+			log.Printf("Warning: could not determine GitHub identity on %s for manifest: %v", host, err)
+			continue
+		}
+		identities = append(identities, fmt.Sprintf("%s@%s", identity, host))
+	}
+	manifest.GitHubIdentity = strings.Join(identities, ", ")
+
+	manifestDir, err := filepath.Abs(filepath.Dir(manifestFile))
+	if err != nil {
+		return err
+	}
+	for _, path := range generatedArtifacts {
+		sum, size, err := hashFile(path)
+		if err != nil {
+			return fmt.Errorf("failed to hash %s: %w", path, err)
+		}
+
+		absPath, err := filepath.Abs(path)
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(manifestDir, absPath)
+		if err != nil {
+			return err
+		}
+
+		manifest.Artifacts = append(manifest.Artifacts, ManifestArtifact{
+			Path:   filepath.ToSlash(rel),
+			Size:   size,
+			SHA256: sum,
+		})
+	}
+
+	if signKeyFile != "" {
+		privateKey, err := loadPrivateKey(signKeyFile)
+		if err != nil {
+			return fmt.Errorf("failed to load signing key: %w", err)
+		}
+
+		manifest.PublicKey = base64.StdEncoding.EncodeToString(privateKey.Public().(ed25519.PublicKey))
+		payload, err := manifestSigningPayload(manifest)
+		if err != nil {
+			return err
+		}
+		manifest.Signature = base64.StdEncoding.EncodeToString(ed25519.Sign(privateKey, payload))
+	}
+
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(manifestFile, data, 0644); err != nil {
+		return err
+	}
+
+	if manifest.Signature != "" {
+		fmt.Printf("🔏 Signed manifest generated: %s\n", manifestFile)
+	} else {
+		fmt.Printf("🔐 Manifest generated: %s\n", manifestFile)
+	}
+	return nil
+}
+
+func runVerify(cmd *cobra.Command, args []string) {
+	manifestFile := args[0]
+
+	data, err := os.ReadFile(manifestFile)
+	if err != nil {
+		log.Fatalf("Failed to read manifest: %v", err)
+	}
+
+	var manifest ReportManifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		log.Fatalf("Failed to parse manifest: %v", err)
+	}
+
+	failures := 0
+
+	// Check the signature first so a forged manifest is reported before its hashes
+	if manifest.Signature != "" {
+		if err := verifyManifestSignature(manifest); err != nil {
+			fmt.Printf("❌ Signature: %v\n", err)
+			failures++
+		} else if publicKeyFile == "" {
+			fmt.Printf("⚠️  Signature: valid for the embedded public key only (pass --public-key to check the signer)\n")
+		} else {
+			fmt.Printf("✅ Signature: valid\n")
+		}
+	} else if publicKeyFile != "" {
+		fmt.Printf("❌ Signature: manifest is not signed\n")
+		failures++
+	}
+
+	manifestDir := filepath.Dir(manifestFile)
+	for _, artifact := range manifest.Artifacts {
+		path := filepath.Join(manifestDir, filepath.FromSlash(artifact.Path))
+		sum, size, err := hashFile(path)
+		switch {
+		case err != nil:
+			fmt.Printf("❌ %s: %v\n", artifact.Path, err)
+			failures++
+		case sum != artifact.SHA256 || size != artifact.Size:
+			fmt.Printf("❌ %s: SHA-256 mismatch (expected %s, got %s)\n", artifact.Path, artifact.SHA256, sum)
+			failures++
+		default:
+			fmt.Printf("✅ %s\n", artifact.Path)
+		}
+	}
+
+	fmt.Printf("\nGenerated %s by %s %s as %q\n", manifest.Generated.Format("2006-01-02 15:04:05 MST"),
+		manifest.Tool, manifest.ToolVersion, manifest.GitHubIdentity)
+
+	if failures > 0 {
+		log.Fatalf("Verification failed: %d problem(s) found", failures)
+	}
+	fmt.Printf("🎉 All %d artifacts verified\n", len(manifest.Artifacts))
+}
+
+// verifyManifestSignature checks the manifest signature against the trusted public key,
+// falling back to the key embedded in the manifest
+func verifyManifestSignature(manifest ReportManifest) error {
+	embedded, err := base64.StdEncoding.DecodeString(manifest.PublicKey)
+	if err != nil || len(embedded) != ed25519.PublicKeySize {
+		return fmt.Errorf("invalid embedded public key")
+	}
+	publicKey := ed25519.PublicKey(embedded)
+
+	if publicKeyFile != "" {
+		trusted, err := loadPublicKey(publicKeyFile)
+		if err != nil {
+			return fmt.Errorf("failed to load public key: %w", err)
+		}
+		if !trusted.Equal(publicKey) {
+			return fmt.Errorf("manifest was signed by a different key")
+		}
+		publicKey = trusted
+	}
+
+	signature, err := base64.StdEncoding.DecodeString(manifest.Signature)
+	if err != nil {
+		return fmt.Errorf("invalid signature encoding: %w", err)
+	}
+
+	payload, err := manifestSigningPayload(manifest)
+	if err != nil {
+		return err
+	}
+	if !ed25519.Verify(publicKey, payload, signature) {
+		return fmt.Errorf("signature does not match manifest contents")
+	}
+
+	return nil
+}
+
+// manifestSigningPayload returns the bytes that are signed: the compact JSON manifest without its signature
+func manifestSigningPayload(manifest ReportManifest) ([]byte, error) {
+	manifest.Signature = ""
+	return json.Marshal(manifest)
+}
+
+// hashFile returns the hex SHA-256 and size of a file
+func hashFile(path string) (string, int64, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", 0, err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), int64(len(data)), nil
+}
+
+// loadPrivateKey reads an ed25519 private key from a PEM (PKCS#8) file or a
+// base64/hex encoded 32-byte seed or 64-byte private key
+func loadPrivateKey(filename string) (ed25519.PrivateKey, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	if block, _ := pem.Decode(data); block != nil {
+		key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		privateKey, ok := key.(ed25519.PrivateKey)
+		if !ok {
+			return nil, fmt.Errorf("%s is not an ed25519 private key", filename)
+		}
+		return privateKey, nil
+	}
+
+	raw, err := decodeKeyText(data)
+	if err != nil {
+		return nil, err
+	}
+	switch len(raw) {
+	case ed25519.SeedSize:
+		return ed25519.NewKeyFromSeed(raw), nil
+	case ed25519.PrivateKeySize:
+		return ed25519.PrivateKey(raw), nil
+	default:
+		return nil, fmt.Errorf("%s: unexpected ed25519 private key length %d", filename, len(raw))
+	}
+}
+
+// loadPublicKey reads an ed25519 public key from a PEM (PKIX) file or base64/hex text
+func loadPublicKey(filename string) (ed25519.PublicKey, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	if block, _ := pem.Decode(data); block != nil {
+		key, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		publicKey, ok := key.(ed25519.PublicKey)
+		if !ok {
+			return nil, fmt.Errorf("%s is not an ed25519 public key", filename)
+		}
+		return publicKey, nil
+	}
+
+	raw, err := decodeKeyText(data)
+	if err != nil {
+		return nil, err
+	}
+	if len(raw) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("%s: unexpected ed25519 public key length %d", filename, len(raw))
+	}
+	return ed25519.PublicKey(raw), nil
+}
+
+// decodeKeyText decodes hex or base64 key material
+func decodeKeyText(data []byte) ([]byte, error) {
+	text := strings.TrimSpace(string(data))
+	if raw, err := hex.DecodeString(text); err == nil {
+		return raw, nil
+	}
+	raw, err := base64.StdEncoding.DecodeString(text)
+	if err != nil {
+		return nil, fmt.Errorf("key is neither PEM, hex nor base64 encoded")
+	}
+	return raw, nil
+}
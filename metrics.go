@@ -0,0 +1,278 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/tealeg/xlsx/v3"
+)
+
+// prSizeBuckets are the PR size classes by lines changed (additions plus deletions)
+var prSizeBuckets = []struct {
+	Name     string
+	MaxLines int // Exclusive upper bound; 0 for the last bucket
+}{
+	{"XS", 10},
+	{"S", 100},
+	{"M", 500},
+	{"L", 1000},
+	{"XL", 0},
+}
+
+// sizeUnknown is the size class of PRs whose changed lines are not known
+const sizeUnknown = "Unknown"
+
+// metricsGroup holds the delivery metrics of a repository, vertical or author
+type metricsGroup struct {
+	Name            string
+	Merged          int
+	Reverts         int             // Merged PRs that revert earlier changes
+	Reverted        int             // Merged PRs reverted by a later PR
+	LeadTimes       []time.Duration // Opened to merged
+	FirstReviews    []time.Duration // Opened to the first review by someone other than the author
+	ApprovalToMerge []time.Duration // Last approval to merged
+	Sizes           map[string]int  // PRs per size class
+}
+
+// metricsScope is a set of metrics groups such as all repositories or all authors
+type metricsScope struct {
+	Name   string
+	Groups []*metricsGroup
+}
+
+// buildMetrics computes the delivery metrics of merged PRs per repository, vertical and author
+func buildMetrics(merged []PRRecord) []metricsScope {
+	scopes := []metricsScope{
+		{Name: "Repository", Groups: groupMetrics(merged, func(item PRRecord) []string { return []string{item.RepositoryKey()} })},
+	}
+	if hasVerticals(merged) {
+		scopes = append(scopes, metricsScope{Name: "Vertical", Groups: groupMetrics(merged, recordVerticals)})
+	}
+	scopes = append(scopes, metricsScope{Name: "Author", Groups: groupMetrics(merged, func(item PRRecord) []string {
+		if item.PR.Author.Login == "" {
+			return []string{"Unknown"}
+		}
+		return []string{personName(item.PR.Author.Login)}
+	})})
+	return scopes
+}
+
+// groupMetrics computes the metrics of each group returned by key, sorted by name
+func groupMetrics(merged []PRRecord, key func(PRRecord) []string) []*metricsGroup {
+	groups := make(map[string]*metricsGroup)
+	for _, item := range merged {
+		for _, name := range key(item) {
+			group, ok := groups[name]
+			if !ok {
+				group = &metricsGroup{Name: name, Sizes: make(map[string]int)}
+				groups[name] = group
+			}
+			group.add(item)
+		}
+	}
+
+	var sorted []*metricsGroup
+	for _, name := range sortedKeys(groups) {
+		sorted = append(sorted, groups[name])
+	}
+	return sorted
+}
+
+// add accumulates the metrics of a merged pull request
+func (g *metricsGroup) add(item PRRecord) {
+	pr := item.PR
+	g.Merged++
+	g.Sizes[prSizeClass(pr)]++
+	if pr.IsRevert {
+		g.Reverts++
+	}
+	if isReverted(pr) {
+		g.Reverted++
+	}
+	if pr.MergedAt == nil {
+		return
+	}
+	if !pr.CreatedAt.IsZero() {
+		g.LeadTimes = append(g.LeadTimes, pr.MergedAt.Sub(pr.CreatedAt))
+	}
+
+	// Local history and GitLab approvals carry no review times of their own
+	if pr.Provenance == provenanceLocal || item.Provider == providerGitLab {
+		return
+	}
+	if first := firstReviewAt(pr); first != nil && !pr.CreatedAt.IsZero() {
+		g.FirstReviews = append(g.FirstReviews, first.Sub(pr.CreatedAt))
+	}
+	if last := lastApprovalAt(pr); last != nil {
+		g.ApprovalToMerge = append(g.ApprovalToMerge, pr.MergedAt.Sub(*last))
+	}
+}
+
+// firstReviewAt returns when someone other than the author first reviewed the PR before it merged
+func firstReviewAt(pr PullRequest) *time.Time {
+	var first *time.Time
+	for _, review := range pr.Reviews {
+		if review.Author.Login == pr.Author.Login || review.SubmittedAt.IsZero() || review.SubmittedAt.After(*pr.MergedAt) {
+			continue
+		}
+		if first == nil || review.SubmittedAt.Before(*first) {
+			submitted := review.SubmittedAt
+			first = &submitted
+		}
+	}
+	return first
+}
+
+// lastApprovalAt returns when the PR last received a human approval before it merged
+func lastApprovalAt(pr PullRequest) *time.Time {
+	if approval := lastApproval(pr.Author.Login, pr.Reviews, pr.MergedAt); approval != nil {
+		return &approval.SubmittedAt
+	}
+	return nil
+}
+
+// prSizeClass returns the size bucket of a PR
+func prSizeClass(pr PullRequest) string {
+	if !changedLinesKnown(pr) {
+		return sizeUnknown
+	}
+	lines := pr.Additions + pr.Deletions
+	for _, bucket := range prSizeBuckets {
+		if bucket.MaxLines == 0 || lines < bucket.MaxLines {
+			return bucket.Name
+		}
+	}
+	return sizeUnknown
+}
+
+// changeFailureRate returns the share of changes that were reverted, leaving out the reverts
+// themselves, and false when there are no such changes
+func (g *metricsGroup) changeFailureRate() (float64, bool) {
+	changes := g.Merged - g.Reverts
+	if changes <= 0 {
+		return 0, false
+	}
+	return float64(g.Reverted) / float64(changes), true
+}
+
+// percentile returns the nearest-rank percentile of a set of durations, and false when empty
+func percentile(durations []time.Duration, p float64) (time.Duration, bool) {
+	if len(durations) == 0 {
+		return 0, false
+	}
+	sorted := append([]time.Duration{}, durations...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	rank := int(math.Ceil(p/100*float64(len(sorted)))) - 1
+	if rank < 0 {
+		rank = 0
+	}
+	return sorted[rank], true
+}
+
+// formatMetricDuration renders a duration in hours, or days from two days up
+func formatMetricDuration(durations []time.Duration, p float64) string {
+	d, ok := percentile(durations, p)
+	if !ok {
+		return "n/a"
+	}
+	if d < 48*time.Hour {
+		return fmt.Sprintf("%.1fh", d.Hours())
+	}
+	return fmt.Sprintf("%.1fd", d.Hours()/24)
+}
+
+// metricsHeaders are the column headers of the metrics tables after the group name
+func metricsHeaders() []string {
+	headers := []string{"Merged PRs", "Reverted", "Change Failure Rate", "Median Lead Time", "P90 Lead Time", "Median Time to First Review", "Median Approval to Merge"}
+	for _, bucket := range prSizeBuckets {
+		headers = append(headers, bucket.Name)
+	}
+	return append(headers, sizeUnknown)
+}
+
+// generateMetricsSection writes the lead time, review latency and PR size tables
+func generateMetricsSection(output *os.File, merged []PRRecord) {
+	fmt.Fprintf(output, "## ⏱️ Metrics\n\n")
+	fmt.Fprintf(output, "Lead time runs from opening to merge. The change failure rate is the share of merged PRs, other than reverts, that were reverted. PR sizes count added plus deleted lines: ")
+	var sizes []string
+	lower := 0
+	for _, bucket := range prSizeBuckets {
+		if bucket.MaxLines == 0 {
+			sizes = append(sizes, fmt.Sprintf("%s %d+", bucket.Name, lower))
+		} else {
+			sizes = append(sizes, fmt.Sprintf("%s <%d", bucket.Name, bucket.MaxLines))
+			lower = bucket.MaxLines
+		}
+	}
+	fmt.Fprintf(output, "%s.\n\n", strings.Join(sizes, ", "))
+
+	headers := metricsHeaders()
+	for _, scope := range buildMetrics(merged) {
+		fmt.Fprintf(output, "### By %s\n\n", scope.Name)
+		fmt.Fprintf(output, "| %s | %s |\n", scope.Name, strings.Join(headers, " | "))
+		fmt.Fprintf(output, "|---|%s\n", strings.Repeat("---:|", len(headers)))
+		for _, group := range scope.Groups {
+			failureRate := "n/a"
+			if rate, ok := group.changeFailureRate(); ok {
+				failureRate = fmt.Sprintf("%.1f%%", rate*100)
+			}
+			fmt.Fprintf(output, "| %s | %d | %d | %s | %s | %s | %s | %s |", escapeTableCell(group.Name), group.Merged, group.Reverted, failureRate,
+				formatMetricDuration(group.LeadTimes, 50), formatMetricDuration(group.LeadTimes, 90),
+				formatMetricDuration(group.FirstReviews, 50), formatMetricDuration(group.ApprovalToMerge, 50))
+			for _, bucket := range prSizeBuckets {
+				fmt.Fprintf(output, " %d |", group.Sizes[bucket.Name])
+			}
+			fmt.Fprintf(output, " %d |\n", group.Sizes[sizeUnknown])
+		}
+		fmt.Fprintf(output, "\n")
+	}
+}
+
+// writeMetricsSheet writes the metrics of every scope to a worksheet, with durations in hours
+func writeMetricsSheet(sheet *xlsx.Sheet, merged []PRRecord) {
+	bold := xlsxHeaderStyle()
+
+	headers := []string{"Scope", "Name", "Merged PRs", "Reverted", "Change Failure Rate", "Median Lead Time (h)", "P90 Lead Time (h)",
+		"Median Time to First Review (h)", "Median Approval to Merge (h)"}
+	for _, bucket := range prSizeBuckets {
+		headers = append(headers, bucket.Name)
+	}
+	addHeaderRow(sheet, bold, append(headers, sizeUnknown)...)
+
+	hours := func(row *xlsx.Row, durations []time.Duration, p float64) {
+		cell := row.AddCell()
+		if d, ok := percentile(durations, p); ok {
+			cell.SetFloatWithFormat(math.Round(d.Hours()*10)/10, "0.0")
+		}
+	}
+	for _, scope := range buildMetrics(merged) {
+		for _, group := range scope.Groups {
+			row := sheet.AddRow()
+			row.AddCell().SetString(scope.Name)
+			row.AddCell().SetString(group.Name)
+			row.AddCell().SetInt(group.Merged)
+			row.AddCell().SetInt(group.Reverted)
+			cell := row.AddCell()
+			if rate, ok := group.changeFailureRate(); ok {
+				cell.SetFloatWithFormat(rate, "0.0%")
+			}
+			hours(row, group.LeadTimes, 50)
+			hours(row, group.LeadTimes, 90)
+			hours(row, group.FirstReviews, 50)
+			hours(row, group.ApprovalToMerge, 50)
+			for _, bucket := range prSizeBuckets {
+				row.AddCell().SetInt(group.Sizes[bucket.Name])
+			}
+			row.AddCell().SetInt(group.Sizes[sizeUnknown])
+		}
+	}
+
+	sheet.SetColWidth(1, 1, 12)
+	sheet.SetColWidth(2, 2, 30)
+	sheet.SetColWidth(3, len(headers)+1, 16)
+	freezeHeaderRow(sheet)
+}
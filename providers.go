@@ -0,0 +1,146 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"sync"
+)
+
+// Supported pull request providers
+const (
+	providerGitHub      = "github"
+	providerGitLab      = "gitlab"
+	providerBitbucket   = "bitbucket"
+	providerAzureDevOps = "azuredevops"
+	providerLocal       = "local"
+)
+
+// defaultBaseURLs are used for repositories of a hosted provider without a base_url
+var defaultBaseURLs = map[string]string{
+	providerGitLab:      "https://gitlab.com",
+	providerAzureDevOps: "https://dev.azure.com",
+}
+
+// Parts of a pull request fetched separately from the pull request itself
+const (
+	partReviews = "reviews"
+	partFiles   = "changed files"
+	partCommits = "commits"
+)
+
+// markUnverified records a part of a pull request that could not be fetched, so that the PR is
+// reported as unverified instead of failing its whole repository
+func markUnverified(pr *PullRequest, ref, part string, err error) {
+	log.Printf("⚠️ Could not fetch the %s of %s, reporting it as unverified: %v", part, ref, err)
+	pr.Unverified = append(pr.Unverified, fmt.Sprintf("%s (%v)", part, err))
+	switch part {
+	case partReviews:
+		pr.ReviewsUnknown = true
+	case partFiles:
+		pr.FilesIncomplete, pr.LinesUnknown = true, true
+	}
+}
+
+// PullRequestProvider fetches pull requests for a repository from a code hosting platform
+type PullRequestProvider interface {
+	FetchPullRequests(repository Repository, filter *PRFilter, workerConfig *WorkerConfig) ([]PullRequest, error)
+}
+
+// Providers maps provider names to their clients
+type Providers map[string]PullRequestProvider
+
+// NewProviders creates a client for every supported provider
+func NewProviders() Providers {
+	return Providers{
+		providerGitHub:      NewGitHubClient(),
+		providerGitLab:      NewGitLabClient(),
+		providerBitbucket:   NewBitbucketClient(),
+		providerAzureDevOps: NewAzureDevOpsClient(),
+		providerLocal:       NewLocalGitClient(),
+	}
+}
+
+// FetchPullRequests fetches pull requests for a repository from its configured provider
+func (p Providers) FetchPullRequests(repository Repository, filter *PRFilter, workerConfig *WorkerConfig) ([]PullRequest, error) {
+	provider, ok := p[repository.Provider]
+	if !ok {
+		return nil, fmt.Errorf("unsupported provider %q for %s/%s", repository.Provider, repository.Owner, repository.Name)
+	}
+	return provider.FetchPullRequests(repository, filter, workerConfig)
+}
+
+// FetchPullRequestsConcurrent fetches pull requests from multiple repositories concurrently
+func (p Providers) FetchPullRequestsConcurrent(repositories []Repository, filter *PRFilter, workerConfig *WorkerConfig) []RepositoryResult {
+	// Create channels for work distribution and results
+	jobs := make(chan Repository, len(repositories))
+	results := make(chan RepositoryResult, len(repositories))
+
+	// Start workers
+	var wg sync.WaitGroup
+	for i := 0; i < workerConfig.MaxWorkers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for repo := range jobs {
+				prs, err := p.FetchPullRequests(repo, filter, workerConfig)
+				results <- RepositoryResult{
+					Repository: fmt.Sprintf("%s/%s", repo.Owner, repo.Name),
+					Host:       repo.Host,
+					Provider:   repo.Provider,
+					PRs:        prs,
+					Error:      err,
+				}
+			}
+		}()
+	}
+
+	// Send jobs to workers
+	go func() {
+		defer close(jobs)
+		for _, repo := range repositories {
+			jobs <- repo
+		}
+	}()
+
+	// Close results channel when all workers are done
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	// Collect results
+	var allResults []RepositoryResult
+	for result := range results {
+		allResults = append(allResults, result)
+	}
+
+	return allResults
+}
+
+// getJSON performs a GET request with the given headers and decodes the JSON response
+func getJSON(httpClient *http.Client, rawURL string, header http.Header, result interface{}) (http.Header, error) {
+	req, err := http.NewRequest(http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	for key, values := range header {
+		req.Header[key] = values
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, fmt.Errorf("%s returned %s", req.URL.Path, resp.Status)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
+		return nil, fmt.Errorf("failed to parse response from %s: %w", req.URL.Path, err)
+	}
+	return resp.Header, nil
+}
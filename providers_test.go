@@ -0,0 +1,74 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// fakeResponse is a canned API response
+type fakeResponse struct {
+	Status int
+	Header map[string]string
+	Body   string
+}
+
+// newFakeAPI serves canned responses keyed by escaped path and raw query, failing the test on
+// any other request
+func newFakeAPI(t *testing.T, responses map[string]fakeResponse) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := r.URL.EscapedPath()
+		if r.URL.RawQuery != "" {
+			key += "?" + r.URL.RawQuery
+		}
+		response, ok := responses[key]
+		if !ok {
+			t.Errorf("unexpected request %s", key)
+			http.NotFound(w, r)
+			return
+		}
+		for name, value := range response.Header {
+			w.Header().Set(name, value)
+		}
+		if response.Status != 0 {
+			w.WriteHeader(response.Status)
+		}
+		w.Write([]byte(response.Body))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// pullRequestNumbers returns the numbers of pull requests in order
+func pullRequestNumbers(prs []PullRequest) []int {
+	var numbers []int
+	for _, pr := range prs {
+		numbers = append(numbers, pr.Number)
+	}
+	return numbers
+}
+
+// commitOids returns the object IDs of commits in order
+func commitOids(commits []PRCommit) []string {
+	var oids []string
+	for _, commit := range commits {
+		oids = append(oids, commit.Oid)
+	}
+	return oids
+}
+
+// filePaths returns the paths of changed files in order
+func filePaths(files []ChangedFile) []string {
+	var paths []string
+	for _, file := range files {
+		paths = append(paths, file.Path)
+	}
+	return paths
+}
+
+// decodeTestJSON decodes a JSON fixture
+func decodeTestJSON(data string, result interface{}) error {
+	return json.Unmarshal([]byte(data), result)
+}
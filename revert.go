@@ -0,0 +1,225 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	// revertTitlePattern matches the titles GitHub and git give reverts: Revert "original title"
+	revertTitlePattern = regexp.MustCompile(`^Revert\s+"(.+)"$`)
+
+	// revertReferencePattern matches references to a reverted PR, e.g. "Reverts acme/api#123" or "Revert #123"
+	revertReferencePattern = regexp.MustCompile(`(?i)\breverts?\s+([\w.-]+/[\w.-]+)?#(\d+)\b`)
+
+	// revertURLPattern matches links to a reverted PR, e.g. "Reverts https://github.com/acme/api/pull/123"
+	revertURLPattern = regexp.MustCompile(`(?i)\breverts?\s+https?://[^\s/]+/([^\s/]+/[^\s/]+)/pull/(\d+)\b`)
+
+	// revertCommitPattern matches the message git revert writes: "This reverts commit <sha>."
+	revertCommitPattern = regexp.MustCompile(`(?i)\bthis reverts commit ([0-9a-f]{7,40})\b`)
+)
+
+// detectReverts marks the merged PRs that revert earlier changes and links each revert to the
+// PRs it reverts within the same repository
+func detectReverts(records []PRRecord) {
+	byRepo := make(map[string][]int)
+	for i, item := range records {
+		if prState(item.PR) == stateMerged {
+			byRepo[item.RepositoryKey()] = append(byRepo[item.RepositoryKey()], i)
+		}
+	}
+
+	for _, indexes := range byRepo {
+		for _, i := range indexes {
+			revert := &records[i].PR
+			originals, isRevert := revertedPRs(records[i].Repository, *revert, records, indexes)
+			if !isRevert {
+				continue
+			}
+			revert.IsRevert = true
+			revert.Reverts = originals
+			for _, j := range indexes {
+				original := &records[j].PR
+				for _, number := range originals {
+					if original.Number == number {
+						original.RevertedBy = append(original.RevertedBy, revert.Number)
+					}
+				}
+			}
+		}
+	}
+}
+
+// revertedPRs returns the numbers of the PRs a PR reverts, and whether it is a revert at all.
+// A revert whose original is outside the report is still a revert, with no PR to link.
+func revertedPRs(repo string, pr PullRequest, records []PRRecord, indexes []int) ([]int, bool) {
+	found := make(map[int]bool)
+	isRevert := false
+
+	// Explicit references in the title or description
+	text := pr.Title + "\n" + pr.Body
+	for _, pattern := range []*regexp.Regexp{revertReferencePattern, revertURLPattern} {
+		for _, match := range pattern.FindAllStringSubmatch(text, -1) {
+			if match[1] != "" && !strings.EqualFold(match[1], repo) {
+				continue
+			}
+			if number, err := strconv.Atoi(match[2]); err == nil && number != pr.Number {
+				found[number] = true
+				isRevert = true
+			}
+		}
+	}
+
+	// Commits made with git revert name the commit they revert. Named in the description, the
+	// commit marks a revert; in a commit message, only a commit of another PR does, since a branch
+	// may revert its own work in progress.
+	for _, match := range revertCommitPattern.FindAllStringSubmatch(pr.Body, -1) {
+		isRevert = true
+		markRevertedCommit(strings.ToLower(match[1]), pr, records, indexes, found)
+	}
+	for _, message := range commitMessages(pr) {
+		for _, match := range revertCommitPattern.FindAllStringSubmatch(message, -1) {
+			if sha := strings.ToLower(match[1]); !containsCommit(pr, sha) && markRevertedCommit(sha, pr, records, indexes, found) {
+				isRevert = true
+			}
+		}
+	}
+
+	// Reverts made from the web UI quote the original title; the latest earlier PR with that title is the original
+	if match := revertTitlePattern.FindStringSubmatch(strings.TrimSpace(pr.Title)); match != nil {
+		isRevert = true
+		if len(found) == 0 {
+			var latest *PullRequest
+			for _, j := range indexes {
+				original := &records[j].PR
+				if original.Number == pr.Number || original.Title != match[1] || !original.MergedAt.Before(*pr.MergedAt) {
+					continue
+				}
+				if latest == nil || original.MergedAt.After(*latest.MergedAt) {
+					latest = original
+				}
+			}
+			if latest != nil {
+				found[latest.Number] = true
+			}
+		}
+	}
+
+	var numbers []int
+	for number := range found {
+		numbers = append(numbers, number)
+	}
+	sort.Ints(numbers)
+	return numbers, isRevert
+}
+
+// markRevertedCommit records the PRs other than pr that contain a commit, and reports whether any does
+func markRevertedCommit(sha string, pr PullRequest, records []PRRecord, indexes []int, found map[int]bool) bool {
+	marked := false
+	for _, j := range indexes {
+		if original := records[j].PR; original.Number != pr.Number && containsCommit(original, sha) {
+			found[original.Number] = true
+			marked = true
+		}
+	}
+	return marked
+}
+
+// commitMessages returns the full messages of a PR's commits
+func commitMessages(pr PullRequest) []string {
+	var messages []string
+	for _, commit := range pr.Commits {
+		messages = append(messages, commit.MessageHeadline+"\n"+commit.MessageBody)
+	}
+	return messages
+}
+
+// containsCommit reports whether a PR merged as, or contains, a commit given by a full or
+// abbreviated SHA
+func containsCommit(pr PullRequest, sha string) bool {
+	matches := func(oid string) bool {
+		return oid != "" && strings.HasPrefix(strings.ToLower(oid), sha)
+	}
+	if pr.MergeCommit != nil && matches(pr.MergeCommit.Oid) {
+		return true
+	}
+	for _, commit := range pr.Commits {
+		if matches(commit.Oid) {
+			return true
+		}
+	}
+	return false
+}
+
+// revertRecords returns the merged PRs that revert earlier changes
+func revertRecords(records []PRRecord) []PRRecord {
+	var reverts []PRRecord
+	for _, item := range records {
+		if item.PR.IsRevert {
+			reverts = append(reverts, item)
+		}
+	}
+	return reverts
+}
+
+// isReverted reports whether a PR was reverted by a later PR in the report
+func isReverted(pr PullRequest) bool {
+	return len(pr.RevertedBy) > 0
+}
+
+// prNumbers renders PR numbers as #N references
+func prNumbers(numbers []int) string {
+	var refs []string
+	for _, number := range numbers {
+		refs = append(refs, fmt.Sprintf("#%d", number))
+	}
+	return strings.Join(refs, ", ")
+}
+
+// generateRevertSection lists each revert with the PRs it rolled back and how long they were live
+func generateRevertSection(output *os.File, merged []PRRecord) {
+	reverts := revertRecords(merged)
+	reverted := 0
+	byNumber := make(map[string]PullRequest)
+	for _, item := range merged {
+		byNumber[fmt.Sprintf("%s#%d", item.RepositoryKey(), item.PR.Number)] = item.PR
+		if isReverted(item.PR) {
+			reverted++
+		}
+	}
+
+	fmt.Fprintf(output, "## ⏪ Reverts\n\n")
+	fmt.Fprintf(output, "- **Revert PRs:** %d\n", len(reverts))
+	fmt.Fprintf(output, "- **Reverted PRs:** %d\n\n", reverted)
+
+	fmt.Fprintf(output, "| Revert | Title | Merged | Reverts | Original Merged | Time to Revert |\n")
+	fmt.Fprintf(output, "|---|---|---|---|---|---:|\n")
+	for _, item := range reverts {
+		originals, originalMerged, timeToRevert := "unknown", "", ""
+		if len(item.PR.Reverts) > 0 {
+			originals = prNumbers(item.PR.Reverts)
+			var dates []string
+			var durations []time.Duration
+			for _, number := range item.PR.Reverts {
+				original, ok := byNumber[fmt.Sprintf("%s#%d", item.RepositoryKey(), number)]
+				if !ok || original.MergedAt == nil {
+					continue
+				}
+				dates = append(dates, original.MergedAt.Format("2006-01-02"))
+				durations = append(durations, item.PR.MergedAt.Sub(*original.MergedAt))
+			}
+			originalMerged = strings.Join(dates, ", ")
+			if len(durations) > 0 {
+				timeToRevert = formatMetricDuration(durations, 100)
+			}
+		}
+		fmt.Fprintf(output, "| %s | %s | %s | %s | %s | %s |\n", markdownLink(fmt.Sprintf("%s#%d", item.Repository, item.PR.Number), item.URL()),
+			escapeTableCell(item.PR.Title), item.PR.MergedAt.Format("2006-01-02"), originals, originalMerged, timeToRevert)
+	}
+	fmt.Fprintf(output, "\n---\n\n")
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestRevertedPRs(t *testing.T) {
+	merged := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	original := PullRequest{
+		Number:      10,
+		Title:       "Add billing",
+		MergedAt:    &merged,
+		MergeCommit: &CommitRef{Oid: "aaaaaaa1111111111111111111111111111111111"},
+		Commits:     []PRCommit{{Oid: "bbbbbbb2222222222222222222222222222222222"}},
+	}
+	later := merged.Add(24 * time.Hour)
+	revertCommit := func(sha string) PRCommit {
+		return PRCommit{Oid: "ccccccc3333333333333333333333333333333333", MessageHeadline: "Revert \"wip\"", MessageBody: "This reverts commit " + sha + "."}
+	}
+
+	tests := []struct {
+		name       string
+		pr         PullRequest
+		wantPRs    []int
+		wantRevert bool
+	}{
+		{
+			name:       "commit reverting another PR's commit",
+			pr:         PullRequest{Number: 11, Title: "Roll back billing", Commits: []PRCommit{revertCommit("bbbbbbb")}},
+			wantPRs:    []int{10},
+			wantRevert: true,
+		},
+		{
+			name:       "commit reverting another PR's merge commit",
+			pr:         PullRequest{Number: 11, Title: "Roll back billing", Commits: []PRCommit{revertCommit("aaaaaaa1")}},
+			wantPRs:    []int{10},
+			wantRevert: true,
+		},
+		{
+			name: "commit reverting a commit of its own branch",
+			pr: PullRequest{Number: 11, Title: "Add invoices", Commits: []PRCommit{
+				{Oid: "ddddddd4444444444444444444444444444444444", MessageHeadline: "wip"},
+				revertCommit("ddddddd"),
+			}},
+			wantRevert: false,
+		},
+		{
+			name:       "commit reverting a commit outside the report",
+			pr:         PullRequest{Number: 11, Title: "Add invoices", Commits: []PRCommit{revertCommit("eeeeeee")}},
+			wantRevert: false,
+		},
+		{
+			name:       "description naming a commit outside the report",
+			pr:         PullRequest{Number: 11, Title: "Roll back", Body: "This reverts commit eeeeeee."},
+			wantRevert: true,
+		},
+		{
+			name:       "reference in the description",
+			pr:         PullRequest{Number: 11, Title: "Roll back", Body: "Reverts acme/api#10"},
+			wantPRs:    []int{10},
+			wantRevert: true,
+		},
+		{
+			name:       "web UI title",
+			pr:         PullRequest{Number: 11, Title: `Revert "Add billing"`},
+			wantPRs:    []int{10},
+			wantRevert: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.pr.MergedAt = &later
+			records := []PRRecord{{Repository: "acme/api", PR: original}, {Repository: "acme/api", PR: tt.pr}}
+			numbers, isRevert := revertedPRs("acme/api", tt.pr, records, []int{0, 1})
+			if isRevert != tt.wantRevert || !reflect.DeepEqual(numbers, tt.wantPRs) {
+				t.Errorf("revertedPRs = %v, %t, want %v, %t", numbers, isRevert, tt.wantPRs, tt.wantRevert)
+			}
+		})
+	}
+}
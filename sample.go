@@ -0,0 +1,390 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"math"
+	"math/rand"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/tealeg/xlsx/v3"
+)
+
+var (
+	sampleSeed          int64
+	sampleSize          int
+	samplePercent       float64
+	sampleConfidence    int
+	sampleTolerableRate float64
+	sampleStratify      string
+	sampleOutput        string
+)
+
+// aicpaSampleSizes holds statistical sample sizes for tests of controls assuming
+// zero expected deviations, keyed by confidence level and tolerable deviation rate (%)
+var aicpaSampleSizes = map[int]map[float64]int{
+	90: {2: 114, 3: 76, 4: 57, 5: 45, 6: 38, 7: 32, 8: 28, 9: 25, 10: 22, 15: 15, 20: 11},
+	95: {2: 149, 3: 99, 4: 74, 5: 59, 6: 49, 7: 42, 8: 36, 9: 32, 10: 29, 15: 19, 20: 14},
+}
+
+// SampleParameters records everything needed to re-derive a sample selection
+type SampleParameters struct {
+	Seed           int64     `json:"seed"`
+	PopulationHash string    `json:"populationHash"`
+	PopulationSize int       `json:"populationSize"`
+	SampleSize     int       `json:"sampleSize"`
+	Method         string    `json:"method"`
+	Stratification string    `json:"stratification"`
+	StartDate      string    `json:"startDate,omitempty"`
+	EndDate        string    `json:"endDate,omitempty"`
+	Generated      time.Time `json:"generated"`
+}
+
+// SampleItem represents a single pull request selected for the sample
+type SampleItem struct {
+	Stratum string `json:"stratum"`
+	PRRecord
+}
+
+// SampleResult is the sample worksheet written for auditors
+type SampleResult struct {
+	Parameters SampleParameters `json:"parameters"`
+	Items      []SampleItem     `json:"items"`
+}
+
+func newSampleCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "sample",
+		Short: "Draw a reproducible audit sample from the merged pull request population",
+		Long:  "Fetches the merged pull request population and draws a seeded, optionally stratified random sample for auditor testing",
+		Run:   runSample,
+	}
+
+	cmd.Flags().Int64Var(&sampleSeed, "seed", 0, "Random seed for the selection (0 = derive from current time)")
+	cmd.Flags().IntVar(&sampleSize, "size", 0, "Fixed total sample size (overrides --percent and table-based sizing)")
+	cmd.Flags().Float64Var(&samplePercent, "percent", 0, "Sample size as a percentage of the population")
+	cmd.Flags().IntVar(&sampleConfidence, "confidence", 90, "Confidence level for table-based sizing (90 or 95)")
+	cmd.Flags().Float64Var(&sampleTolerableRate, "tolerable-rate", 5, "Tolerable deviation rate in percent for table-based sizing")
+	cmd.Flags().StringVar(&sampleStratify, "stratify", "none", "Stratify the sample by: none, repository or vertical")
+	cmd.Flags().StringVarP(&sampleOutput, "output", "o", "pr-sample.xlsx", "Output sample worksheet (a .json copy is written alongside)")
+
+	return cmd
+}
+
+func runSample(cmd *cobra.Command, args []string) {
+	if sampleStratify != "none" && sampleStratify != "repository" && sampleStratify != "vertical" {
+		log.Fatalf("Invalid --stratify value %q (expected none, repository or vertical)", sampleStratify)
+	}
+
+	_, allPRs := collectPullRequests()
+
+	// Only merged PRs make up the audit population
+	var population []PRRecord
+	for _, item := range allPRs {
+		if item.PR.MergedAt != nil && item.PR.State == "MERGED" {
+			population = append(population, item)
+		}
+	}
+	if len(population) == 0 {
+		log.Fatalf("No merged pull requests found to sample from")
+	}
+
+	size, method, err := determineSampleSize(len(population))
+	if err != nil {
+		log.Fatalf("Failed to determine sample size: %v", err)
+	}
+
+	seed := sampleSeed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+
+	result := drawSample(population, size, seed, sampleStratify)
+	result.Parameters.Method = method
+	result.Parameters.StartDate = startDate
+	result.Parameters.EndDate = endDate
+
+	fmt.Printf("\n🎲 Sampled %d of %d merged pull requests (seed %d)\n", len(result.Items), len(population), seed)
+	fmt.Printf("🔐 Population hash: %s\n", result.Parameters.PopulationHash)
+
+	if err := writeSampleJSON(result); err != nil {
+		log.Fatalf("Failed to write sample JSON: %v", err)
+	}
+	if err := writeSampleXLSX(result); err != nil {
+		log.Fatalf("Failed to write sample worksheet: %v", err)
+	}
+}
+
+// determineSampleSize returns the total sample size and a description of how it was derived
+func determineSampleSize(populationSize int) (int, string, error) {
+	var size int
+	var method string
+
+	switch {
+	case sampleSize > 0:
+		size = sampleSize
+		method = fmt.Sprintf("Fixed count of %d", sampleSize)
+	case samplePercent > 0:
+		size = int(math.Ceil(float64(populationSize) * samplePercent / 100))
+		method = fmt.Sprintf("%.2f%% of population", samplePercent)
+	default:
+		sizes, ok := aicpaSampleSizes[sampleConfidence]
+		if !ok {
+			return 0, "", fmt.Errorf("unsupported confidence level %d (expected 90 or 95)", sampleConfidence)
+		}
+		tableSize, ok := sizes[sampleTolerableRate]
+		if !ok {
+			return 0, "", fmt.Errorf("unsupported tolerable rate %.0f%% (expected one of 2-10, 15 or 20)", sampleTolerableRate)
+		}
+
+		// Apply the finite population correction for small populations
+		size = int(math.Ceil(float64(tableSize) / (1 + float64(tableSize)/float64(populationSize))))
+		method = fmt.Sprintf("AICPA table: %d%% confidence, %.0f%% tolerable deviation rate, zero expected deviations (table size %d, finite population corrected)",
+			sampleConfidence, sampleTolerableRate, tableSize)
+	}
+
+	if size > populationSize {
+		size = populationSize
+	}
+	if size < 1 {
+		size = 1
+	}
+
+	return size, method, nil
+}
+
+// drawSample selects size records from the population using a seeded random permutation
+// per stratum. The population is sorted by repository and PR number first, so the same
+// seed and population always yield the same selection.
+func drawSample(population []PRRecord, size int, seed int64, stratify string) SampleResult {
+	sorted := make([]PRRecord, len(population))
+	copy(sorted, population)
+	sortPRRecords(sorted)
+
+	// Group the population into strata
+	strata := make(map[string][]PRRecord)
+	for _, item := range sorted {
+		key := sampleStratum(item, stratify)
+		strata[key] = append(strata[key], item)
+	}
+	var stratumNames []string
+	for name := range strata {
+		stratumNames = append(stratumNames, name)
+	}
+	sort.Strings(stratumNames)
+
+	allocation := allocateSample(stratumNames, strata, size)
+
+	rng := rand.New(rand.NewSource(seed))
+	var items []SampleItem
+	for _, name := range stratumNames {
+		members := strata[name]
+		perm := rng.Perm(len(members))
+		var selected []PRRecord
+		for _, idx := range perm[:allocation[name]] {
+			selected = append(selected, members[idx])
+		}
+		sortPRRecords(selected)
+		for _, item := range selected {
+			items = append(items, SampleItem{Stratum: name, PRRecord: item})
+		}
+	}
+
+	return SampleResult{
+		Parameters: SampleParameters{
+			Seed:           seed,
+			PopulationHash: populationHash(sorted),
+			PopulationSize: len(sorted),
+			SampleSize:     len(items),
+			Stratification: stratify,
+			Generated:      time.Now(),
+		},
+		Items: items,
+	}
+}
+
+// allocateSample distributes the total sample size across strata proportionally,
+// using the largest remainder method and at least one item per stratum when possible
+func allocateSample(names []string, strata map[string][]PRRecord, size int) map[string]int {
+	allocation := make(map[string]int)
+	total := 0
+	for _, name := range names {
+		total += len(strata[name])
+	}
+
+	type remainder struct {
+		name  string
+		value float64
+	}
+	var remainders []remainder
+	allocated := 0
+	for _, name := range names {
+		exact := float64(size) * float64(len(strata[name])) / float64(total)
+		allocation[name] = int(math.Floor(exact))
+		if allocation[name] == 0 && size >= len(names) {
+			allocation[name] = 1
+		}
+		allocated += allocation[name]
+		remainders = append(remainders, remainder{name: name, value: exact - math.Floor(exact)})
+	}
+
+	sort.SliceStable(remainders, func(i, j int) bool {
+		return remainders[i].value > remainders[j].value
+	})
+	for i := 0; allocated < size && i < len(remainders)*2; i++ {
+		name := remainders[i%len(remainders)].name
+		if allocation[name] < len(strata[name]) {
+			allocation[name]++
+			allocated++
+		}
+	}
+
+	// Minimum allocations may overshoot the requested size; trim from the largest strata
+	for allocated > size {
+		largest := ""
+		for _, name := range names {
+			if largest == "" || allocation[name] > allocation[largest] {
+				largest = name
+			}
+		}
+		allocation[largest]--
+		allocated--
+	}
+
+	for _, name := range names {
+		if allocation[name] > len(strata[name]) {
+			allocation[name] = len(strata[name])
+		}
+	}
+
+	return allocation
+}
+
+// sampleStratum returns the stratum a record belongs to
+func sampleStratum(item PRRecord, stratify string) string {
+	switch stratify {
+	case "repository":
+		return item.RepositoryKey()
+	case "vertical":
+		return strings.Join(recordVerticals(item), "/")
+	default:
+		return "All"
+	}
+}
+
+// sortPRRecords sorts records by repository (including its host), PR number, merge date and
+// title, so that the order never depends on the order PRs were fetched in
+func sortPRRecords(records []PRRecord) {
+	sort.SliceStable(records, func(i, j int) bool {
+		a, b := records[i], records[j]
+		if a.RepositoryKey() != b.RepositoryKey() {
+			return a.RepositoryKey() < b.RepositoryKey()
+		}
+		if a.PR.Number != b.PR.Number {
+			return a.PR.Number < b.PR.Number
+		}
+		if !mergedAt(a.PR).Equal(mergedAt(b.PR)) {
+			return mergedAt(a.PR).Before(mergedAt(b.PR))
+		}
+		return a.PR.Title < b.PR.Title
+	})
+}
+
+// mergedAt returns the merge date of a pull request, or the zero time when it was not merged
+func mergedAt(pr PullRequest) time.Time {
+	if pr.MergedAt == nil {
+		return time.Time{}
+	}
+	return *pr.MergedAt
+}
+
+// populationHash computes a SHA-256 over the sorted population so auditors can
+// confirm a sample was drawn from the same set of pull requests
+func populationHash(sorted []PRRecord) string {
+	h := sha256.New()
+	for _, item := range sorted {
+		fmt.Fprintf(h, "%s#%d\t%s\n", item.RepositoryKey(), item.PR.Number, mergedAt(item.PR).UTC().Format(time.RFC3339))
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func writeSampleJSON(result SampleResult) error {
+	jsonFile := strings.TrimSuffix(sampleOutput, ".xlsx") + ".json"
+
+	data, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(jsonFile, data, 0644); err != nil {
+		return err
+	}
+
+	fmt.Printf("🧾 Sample JSON generated: %s\n", jsonFile)
+	return nil
+}
+
+func writeSampleXLSX(result SampleResult) error {
+	file := xlsx.NewFile()
+
+	params, err := file.AddSheet("Parameters")
+	if err != nil {
+		return err
+	}
+	addRow := func(label, value string) {
+		row := params.AddRow()
+		row.AddCell().SetString(label)
+		row.AddCell().SetString(value)
+	}
+	addRow("Seed", fmt.Sprintf("%d", result.Parameters.Seed))
+	addRow("Population Hash (SHA-256)", result.Parameters.PopulationHash)
+	addRow("Population Size", fmt.Sprintf("%d", result.Parameters.PopulationSize))
+	addRow("Sample Size", fmt.Sprintf("%d", result.Parameters.SampleSize))
+	addRow("Sizing Method", result.Parameters.Method)
+	addRow("Stratification", result.Parameters.Stratification)
+	addRow("Start Date", result.Parameters.StartDate)
+	addRow("End Date", result.Parameters.EndDate)
+	addRow("Generated", result.Parameters.Generated.Format("2006-01-02 15:04:05 MST"))
+	addRow("Selection Procedure", "Population of merged PRs sorted by repository (with its host outside github.com) then PR number, grouped into strata sorted by name; "+
+		"for each stratum in order, Go math/rand (rand.NewSource(seed)).Perm(stratum size) and the first N indices are selected")
+
+	sheet, err := file.AddSheet("Sample")
+	if err != nil {
+		return err
+	}
+	headerRow := sheet.AddRow()
+	headerRow.AddCell().SetString("Stratum")
+	headerRow.AddCell().SetString("Repository")
+	headerRow.AddCell().SetString("PR_Number")
+	headerRow.AddCell().SetString("Title")
+	headerRow.AddCell().SetString("Author")
+	headerRow.AddCell().SetString("Merge_Date")
+
+	for _, item := range result.Items {
+		author := personName(item.PR.Author.Login)
+		if author == "" {
+			author = "Unknown"
+		}
+
+		row := sheet.AddRow()
+		row.AddCell().SetString(item.Stratum)
+		row.AddCell().SetString(item.RepositoryKey())
+		setLinkCell(row.AddCell(), item.URL(), fmt.Sprintf("#%d", item.PR.Number))
+		row.AddCell().SetString(item.PR.Title)
+		row.AddCell().SetString(author)
+		row.AddCell().SetString(item.PR.MergedAt.Format("2006-01-02"))
+	}
+
+	if err := file.Save(sampleOutput); err != nil {
+		return err
+	}
+
+	fmt.Printf("📊 Sample worksheet generated: %s\n", sampleOutput)
+	return nil
+}
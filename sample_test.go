@@ -0,0 +1,149 @@
+package main
+
+import (
+	"fmt"
+	"math/rand"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// sampleTestStrata builds strata of the given sizes named a, b, c...
+func sampleTestStrata(sizes ...int) ([]string, map[string][]PRRecord) {
+	var names []string
+	strata := make(map[string][]PRRecord)
+	for i, size := range sizes {
+		name := string(rune('a' + i))
+		names = append(names, name)
+		for number := 1; number <= size; number++ {
+			strata[name] = append(strata[name], PRRecord{Repository: "acme/" + name, PR: PullRequest{Number: number}})
+		}
+	}
+	return names, strata
+}
+
+func TestAllocateSample(t *testing.T) {
+	tests := []struct {
+		name  string
+		sizes []int
+		size  int
+		want  map[string]int
+	}{
+		{
+			name:  "proportional",
+			sizes: []int{60, 30, 10},
+			size:  10,
+			want:  map[string]int{"a": 6, "b": 3, "c": 1},
+		},
+		{
+			name:  "largest remainders first",
+			sizes: []int{5, 3, 2},
+			size:  5,
+			want:  map[string]int{"a": 3, "b": 1, "c": 1},
+		},
+		{
+			name:  "at least one per stratum",
+			sizes: []int{97, 2, 1},
+			size:  3,
+			want:  map[string]int{"a": 1, "b": 1, "c": 1},
+		},
+		{
+			name:  "fewer items than strata",
+			sizes: []int{8, 1, 1},
+			size:  2,
+			want:  map[string]int{"a": 2, "b": 0, "c": 0},
+		},
+		{
+			name:  "capped at the stratum size",
+			sizes: []int{2, 2},
+			size:  4,
+			want:  map[string]int{"a": 2, "b": 2},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			names, strata := sampleTestStrata(tt.sizes...)
+			got := allocateSample(names, strata, tt.size)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("allocateSample(%v, %d) = %v, want %v", tt.sizes, tt.size, got, tt.want)
+			}
+		})
+	}
+}
+
+// sampleTestPopulation returns merged PRs of two same-named repositories on different hosts
+func sampleTestPopulation() []PRRecord {
+	merged := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	var population []PRRecord
+	for _, host := range []string{"", "gitlab.example.com"} {
+		for _, repo := range []string{"acme/api", "acme/web"} {
+			for number := 1; number <= 10; number++ {
+				at := merged.Add(time.Duration(number) * time.Hour)
+				population = append(population, PRRecord{
+					Repository: repo,
+					Host:       host,
+					PR:         PullRequest{Number: number, MergedAt: &at},
+				})
+			}
+		}
+	}
+	return population
+}
+
+// sampleItemKeys returns the stratum and repository key of each sampled PR
+func sampleItemKeys(items []SampleItem) []string {
+	var keys []string
+	for _, item := range items {
+		keys = append(keys, fmt.Sprintf("%s: %s#%d", item.Stratum, item.RepositoryKey(), item.PR.Number))
+	}
+	return keys
+}
+
+func TestDrawSampleIsReproducible(t *testing.T) {
+	population := sampleTestPopulation()
+	first := drawSample(population, 8, 42, "repository")
+
+	shuffled := make([]PRRecord, len(population))
+	copy(shuffled, population)
+	rand.New(rand.NewSource(7)).Shuffle(len(shuffled), func(i, j int) {
+		shuffled[i], shuffled[j] = shuffled[j], shuffled[i]
+	})
+	second := drawSample(shuffled, 8, 42, "repository")
+
+	if !reflect.DeepEqual(sampleItemKeys(first.Items), sampleItemKeys(second.Items)) {
+		t.Errorf("same seed drew %v, then %v from the shuffled population", sampleItemKeys(first.Items), sampleItemKeys(second.Items))
+	}
+	if first.Parameters.PopulationHash != second.Parameters.PopulationHash {
+		t.Errorf("population hash changed with the fetch order: %s, then %s", first.Parameters.PopulationHash, second.Parameters.PopulationHash)
+	}
+
+	other := drawSample(population, 8, 43, "repository")
+	if reflect.DeepEqual(sampleItemKeys(first.Items), sampleItemKeys(other.Items)) {
+		t.Errorf("seeds 42 and 43 drew the same sample %v", sampleItemKeys(first.Items))
+	}
+}
+
+func TestDrawSampleStratifiesRepositoriesByHost(t *testing.T) {
+	result := drawSample(sampleTestPopulation(), 8, 42, "repository")
+
+	counts := make(map[string]int)
+	for _, item := range result.Items {
+		if item.Stratum != item.RepositoryKey() {
+			t.Errorf("%s#%d is in stratum %q, want %q", item.Repository, item.PR.Number, item.Stratum, item.RepositoryKey())
+		}
+		counts[item.Stratum]++
+	}
+	want := map[string]int{"acme/api": 2, "acme/web": 2, "gitlab.example.com/acme/api": 2, "gitlab.example.com/acme/web": 2}
+	if !reflect.DeepEqual(counts, want) {
+		t.Errorf("sampled per stratum %v, want %v", counts, want)
+	}
+
+	hosted := sampleTestPopulation()
+	for i := range hosted {
+		hosted[i].Host = ""
+	}
+	if populationHash(hosted) == result.Parameters.PopulationHash {
+		t.Error("population hash ignores the repository host")
+	}
+}
@@ -0,0 +1,289 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/tealeg/xlsx/v3"
+)
+
+var (
+	authorFilter   []string
+	reviewerFilter []string
+	mergedByFilter []string
+	teamFilter     []string
+
+	// peopleFilter is the filter of the current run, holding its people scope for the reports
+	peopleFilter *PRFilter
+)
+
+// Roles a person can have on a pull request in a scoped audit
+const (
+	roleAuthored = "Authored"
+	roleApproved = "Approved"
+	roleMerged   = "Merged"
+)
+
+// personRoles lists the roles in report order
+var personRoles = []string{roleAuthored, roleApproved, roleMerged}
+
+// applyPeopleScope adds the --author, --reviewer, --merged-by and --team logins to a filter;
+// team members are in scope in every role
+func applyPeopleScope(filter *PRFilter, hosts []string) error {
+	filter.Authors = lowerLogins(authorFilter)
+	filter.Reviewers = lowerLogins(reviewerFilter)
+	filter.MergedBy = lowerLogins(mergedByFilter)
+	for _, team := range teamFilter {
+		members, err := resolveTeam(team, hosts)
+		if err != nil {
+			return err
+		}
+		members = lowerLogins(members)
+		fmt.Printf("👥 Team %s: %d members\n", team, len(members))
+		filter.Authors = append(filter.Authors, members...)
+		filter.Reviewers = append(filter.Reviewers, members...)
+		filter.MergedBy = append(filter.MergedBy, members...)
+	}
+	return nil
+}
+
+// resolveTeam returns the logins of a team: the people the identity mapping puts in it, or
+// for an "org/team" name the members from --owner-teams or the API of each configured GitHub host
+func resolveTeam(team string, hosts []string) ([]string, error) {
+	var members []string
+	for _, identity := range identities {
+		if strings.EqualFold(identity.Team, team) {
+			members = append(members, identity.Login)
+		}
+	}
+	if len(members) > 0 {
+		sort.Strings(members)
+		return members, nil
+	}
+
+	name := strings.TrimPrefix(team, "@")
+	if !strings.Contains(name, "/") {
+		return nil, fmt.Errorf("team %s has no members in the identity mapping", team)
+	}
+	loadOwnerTeams()
+	if len(hosts) == 0 {
+		hosts = []string{defaultHost}
+	}
+
+	// The team may live on any of the hosts; its members are those found on each
+	seen := make(map[string]bool)
+	var lastErr error
+	for _, host := range hosts {
+		hostMembers, err := teamMembers(PRRecord{Host: host, Provider: providerGitHub}, name)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		for _, member := range hostMembers {
+			seen[member] = true
+		}
+	}
+	if len(seen) == 0 {
+		if lastErr != nil {
+			return nil, lastErr
+		}
+		return nil, fmt.Errorf("team %s has no members", team)
+	}
+	return sortedKeys(seen), nil
+}
+
+// lowerLogins returns logins in lower case for case-insensitive matching
+func lowerLogins(logins []string) []string {
+	var lower []string
+	for _, login := range logins {
+		if login = strings.ToLower(strings.TrimPrefix(strings.TrimSpace(login), "@")); login != "" {
+			lower = append(lower, login)
+		}
+	}
+	return lower
+}
+
+// hasPeopleScope reports whether a filter limits the audit to some people
+func hasPeopleScope(filter *PRFilter) bool {
+	return filter != nil && (len(filter.Authors) > 0 || len(filter.Reviewers) > 0 || len(filter.MergedBy) > 0)
+}
+
+// singleScopedLogin returns the role and login of a filter scoped to one person in one role,
+// which providers can pass to their query
+func singleScopedLogin(filter *PRFilter) (role, login string, ok bool) {
+	if filter == nil {
+		return "", "", false
+	}
+	switch {
+	case len(filter.Authors) == 1 && len(filter.Reviewers) == 0 && len(filter.MergedBy) == 0:
+		return roleAuthored, filter.Authors[0], true
+	case len(filter.Authors) == 0 && len(filter.Reviewers) == 1 && len(filter.MergedBy) == 0:
+		return roleApproved, filter.Reviewers[0], true
+	case len(filter.Authors) == 0 && len(filter.Reviewers) == 0 && len(filter.MergedBy) == 1:
+		return roleMerged, filter.MergedBy[0], true
+	}
+	return "", "", false
+}
+
+// prRoles returns the roles a login has on a pull request
+func prRoles(pr PullRequest, login string) []string {
+	var roles []string
+	if strings.EqualFold(pr.Author.Login, login) {
+		roles = append(roles, roleAuthored)
+	}
+	for _, approver := range approvers(pr) {
+		if strings.EqualFold(approver, login) {
+			roles = append(roles, roleApproved)
+			break
+		}
+	}
+	if pr.MergedBy != nil && strings.EqualFold(pr.MergedBy.Login, login) {
+		roles = append(roles, roleMerged)
+	}
+	return roles
+}
+
+// inPeopleScope reports whether a PR was authored, approved or merged by someone in scope
+func inPeopleScope(pr PullRequest, filter *PRFilter) bool {
+	if containsLogin(filter.Authors, pr.Author.Login) {
+		return true
+	}
+	if pr.MergedBy != nil && containsLogin(filter.MergedBy, pr.MergedBy.Login) {
+		return true
+	}
+	for _, approver := range approvers(pr) {
+		if containsLogin(filter.Reviewers, approver) {
+			return true
+		}
+	}
+	return false
+}
+
+// containsLogin reports whether a login is in a list of lower-case logins
+func containsLogin(logins []string, login string) bool {
+	login = strings.ToLower(login)
+	for _, candidate := range logins {
+		if candidate == login {
+			return true
+		}
+	}
+	return false
+}
+
+// filterByPeople keeps the records in a filter's people scope
+func filterByPeople(records []PRRecord, filter *PRFilter) []PRRecord {
+	if !hasPeopleScope(filter) {
+		return records
+	}
+	var scoped []PRRecord
+	for _, item := range records {
+		if inPeopleScope(item.PR, filter) {
+			scoped = append(scoped, item)
+		}
+	}
+	return scoped
+}
+
+// scopedPeople returns the logins in a filter's people scope, sorted and without duplicates
+func scopedPeople(filter *PRFilter) []string {
+	seen := make(map[string]bool)
+	for _, logins := range [][]string{filter.Authors, filter.Reviewers, filter.MergedBy} {
+		for _, login := range logins {
+			seen[login] = true
+		}
+	}
+	return sortedKeys(seen)
+}
+
+// recordsByRole returns a person's merged PRs for each role they had on them
+func recordsByRole(merged []PRRecord, login string) map[string][]PRRecord {
+	byRole := make(map[string][]PRRecord)
+	for _, item := range merged {
+		for _, role := range prRoles(item.PR, login) {
+			byRole[role] = append(byRole[role], item)
+		}
+	}
+	return byRole
+}
+
+// generatePeopleSections writes a section per person in scope listing the PRs they authored,
+// approved and merged across every repository
+func generatePeopleSections(output *os.File, merged []PRRecord, filter *PRFilter) {
+	for _, login := range scopedPeople(filter) {
+		byRole := recordsByRole(merged, login)
+		fmt.Fprintf(output, "## 👤 %s\n\n", personName(login))
+		if team := personTeam(login); team != "" {
+			fmt.Fprintf(output, "- **Team:** %s\n", team)
+		}
+		for _, role := range personRoles {
+			fmt.Fprintf(output, "- **%s:** %d\n", role, len(byRole[role]))
+		}
+		fmt.Fprintf(output, "\n")
+
+		for _, role := range personRoles {
+			if len(byRole[role]) == 0 {
+				continue
+			}
+			fmt.Fprintf(output, "### %s\n\n", role)
+			fmt.Fprintf(output, "| PR | Title | Author | Merged | Exceptions |\n")
+			fmt.Fprintf(output, "|---|---|---|---|---|\n")
+			for _, item := range byRole[role] {
+				fmt.Fprintf(output, "| %s | %s | %s | %s | %s |\n", markdownLink(fmt.Sprintf("%s#%d", item.Repository, item.PR.Number), item.URL()),
+					escapeTableCell(item.PR.Title), personName(item.PR.Author.Login), item.PR.MergedAt.Format("2006-01-02"),
+					exceptionTypes(item.PR))
+			}
+			fmt.Fprintf(output, "\n")
+		}
+		fmt.Fprintf(output, "---\n\n")
+	}
+}
+
+// writePeopleSheet writes a row per person, role and merged PR
+func writePeopleSheet(sheet *xlsx.Sheet, merged []PRRecord, filter *PRFilter) {
+	bold := xlsxHeaderStyle()
+
+	headers := []string{"Person", "Role"}
+	for _, column := range prColumns {
+		headers = append(headers, column.Header)
+	}
+	addHeaderRow(sheet, bold, headers...)
+
+	rows := 0
+	for _, login := range scopedPeople(filter) {
+		byRole := recordsByRole(merged, login)
+		for _, role := range personRoles {
+			for _, item := range byRole[role] {
+				row := sheet.AddRow()
+				row.AddCell().SetString(personName(login))
+				row.AddCell().SetString(role)
+				addPRCells(row, item)
+				rows++
+			}
+		}
+	}
+
+	sheet.SetColWidth(1, 1, 24)
+	sheet.SetColWidth(2, 2, 12)
+	for i, column := range prColumns {
+		sheet.SetColWidth(i+3, i+3, column.Width)
+	}
+	freezeHeaderRow(sheet)
+	sheet.AutoFilter = &xlsx.AutoFilter{
+		TopLeftCell:     "A1",
+		BottomRightCell: xlsx.GetCellIDStringFromCoords(len(headers)-1, rows),
+	}
+}
+
+// printPeopleScope reports the people a scoped audit covers
+func printPeopleScope(filter *PRFilter) {
+	if !hasPeopleScope(filter) {
+		return
+	}
+	var names []string
+	for _, login := range scopedPeople(filter) {
+		names = append(names, personName(login))
+	}
+	fmt.Printf("👤 Scoped to %s\n", strings.Join(names, ", "))
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// defaultSensitiveApprovals is the number of human approvals required on high-risk changes
+const defaultSensitiveApprovals = 2
+
+// sensitiveApprovals is the configured number of human approvals required on high-risk changes
+var sensitiveApprovals = defaultSensitiveApprovals
+
+// tagSensitiveChanges records the changed files of every PR that match its repository's
+// sensitive path globs. PRs of repositories with sensitive paths whose changed file list is
+// missing or incomplete are marked unverified rather than taken as low-risk.
+func tagSensitiveChanges(config *RepositoriesConfig, records []PRRecord) {
+	compiled := make(map[string][]*regexp.Regexp)
+	var unverified int
+	for i := range records {
+		item := &records[i]
+		key := item.Host + "/" + item.Repository
+		patterns, ok := compiled[key]
+		if !ok {
+			for _, glob := range recordRepository(config, *item).SensitivePaths {
+				pattern, err := pathPatternRegexp(glob)
+				if err != nil {
+					log.Fatalf("Invalid sensitive path %q for %s: %v", glob, item.Repository, err)
+				}
+				patterns = append(patterns, pattern)
+			}
+			compiled[key] = patterns
+		}
+		if len(patterns) == 0 {
+			continue
+		}
+
+		item.PR.SensitivePaths = nil
+		item.PR.SensitiveUnverified = len(item.PR.Files) == 0 || incompleteFiles(item.PR)
+		if item.PR.SensitiveUnverified && prState(item.PR) == stateMerged {
+			unverified++
+		}
+		for _, file := range item.PR.Files {
+			for _, pattern := range patterns {
+				if pattern.MatchString(file.Path) {
+					item.PR.SensitivePaths = append(item.PR.SensitivePaths, file.Path)
+					break
+				}
+			}
+		}
+	}
+
+	if unverified > 0 {
+		log.Printf("⚠️ %d merged PRs have a missing or incomplete changed file list; their sensitive paths are unverified", unverified)
+	}
+}
+
+// isHighRisk reports whether a pull request changed sensitive paths
+func isHighRisk(pr PullRequest) bool {
+	return len(pr.SensitivePaths) > 0
+}
+
+// highRiskRecords returns the records that changed sensitive paths
+func highRiskRecords(records []PRRecord) []PRRecord {
+	var highRisk []PRRecord
+	for _, item := range records {
+		if isHighRisk(item.PR) {
+			highRisk = append(highRisk, item)
+		}
+	}
+	return highRisk
+}
+
+// sensitiveUnverifiedRecords returns the records whose sensitive paths could not be checked
+func sensitiveUnverifiedRecords(records []PRRecord) []PRRecord {
+	var unverified []PRRecord
+	for _, item := range records {
+		if item.PR.SensitiveUnverified {
+			unverified = append(unverified, item)
+		}
+	}
+	return unverified
+}
+
+// generateHighRiskSection lists the PRs that changed sensitive paths with their approvals, and
+// the PRs whose changed files could not all be checked
+func generateHighRiskSection(output *os.File, highRisk, unverified []PRRecord) {
+	fmt.Fprintf(output, "## 🔥 High-Risk Changes\n\n")
+	fmt.Fprintf(output, "- **PRs Changing Sensitive Paths:** %d\n", len(highRisk))
+	if len(unverified) > 0 {
+		fmt.Fprintf(output, "- **PRs With Unverified Sensitive Paths:** %d\n", len(unverified))
+	}
+	fmt.Fprintf(output, "- **Human Approvals Required:** %d\n\n", sensitiveApprovals)
+
+	if len(highRisk) > 0 {
+		fmt.Fprintf(output, "| PR | Title | Author | Human Approvals | Sensitive Paths | Exceptions |\n")
+		fmt.Fprintf(output, "|---|---|---|---:|---|---|\n")
+		for _, item := range highRisk {
+			fmt.Fprintf(output, "| %s | %s | %s | %d | %s | %s |\n", markdownLink(fmt.Sprintf("%s#%d", item.Repository, item.PR.Number), item.URL()),
+				escapeTableCell(item.PR.Title), personName(item.PR.Author.Login), len(humanApprovers(item.PR)),
+				escapeTableCell(strings.Join(item.PR.SensitivePaths, ", ")), exceptionTypes(item.PR))
+		}
+		fmt.Fprintf(output, "\n")
+	}
+
+	if len(unverified) > 0 {
+		fmt.Fprintf(output, "### Unverified\n\n")
+		fmt.Fprintf(output, "The provider did not list all of the files these PRs changed, so they may change sensitive paths.\n\n")
+		fmt.Fprintf(output, "| PR | Title | Author | Human Approvals | Files Listed |\n")
+		fmt.Fprintf(output, "|---|---|---|---:|---:|\n")
+		for _, item := range unverified {
+			fmt.Fprintf(output, "| %s | %s | %s | %d | %d |\n", markdownLink(fmt.Sprintf("%s#%d", item.Repository, item.PR.Number), item.URL()),
+				escapeTableCell(item.PR.Title), personName(item.PR.Author.Login), len(humanApprovers(item.PR)), len(item.PR.Files))
+		}
+		fmt.Fprintf(output, "\n")
+	}
+	fmt.Fprintf(output, "---\n\n")
+}
@@ -0,0 +1,150 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"path"
+	"strings"
+	"sync"
+)
+
+var checkSignatures bool
+
+// signedBranches holds the base branch globs on which commits must be signed; empty means every branch
+var signedBranches []string
+
+// signatureReader is implemented by providers that can report the signature verification of a
+// PR's commits and merge commit, keyed by commit SHA
+type signatureReader interface {
+	CommitSignatures(repository Repository, pr PullRequest) (map[string]Signature, error)
+}
+
+// verifySignatures records the signature verification of every merged PR's commits and merge commit
+func verifySignatures(config *RepositoriesConfig, records []PRRecord) {
+	providers := NewProviders()
+	unsupported := make(map[string]bool)
+	var unverified int
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	semaphore := make(chan struct{}, max(maxWorkers, 1))
+	for i := range records {
+		item := &records[i]
+		if prState(item.PR) != stateMerged {
+			continue
+		}
+
+		repository := recordRepository(config, *item)
+		reader, ok := providers[repository.Provider].(signatureReader)
+		if !ok {
+			if !unsupported[repository.Provider] {
+				unsupported[repository.Provider] = true
+				log.Printf("⚠️ Signed commit verification is not supported for %s repositories", repository.Provider)
+			}
+			continue
+		}
+
+		wg.Add(1)
+		semaphore <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-semaphore }()
+
+			signatures, err := reader.CommitSignatures(repository, item.PR)
+			if err != nil {
+				log.Printf("⚠️ Could not read commit signatures for %s#%d: %v", item.Repository, item.PR.Number, err)
+				return
+			}
+			applySignatures(item.PR.Commits, item.PR.MergeCommit, signatures)
+			if len(unsignedCommits(item.PR)) > 0 {
+				mu.Lock()
+				unverified++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	fmt.Printf("🔏 Signatures: %d PRs with unsigned or unverified commits\n", unverified)
+}
+
+// applySignatures attaches the signatures read from a provider to commits and the merge commit
+func applySignatures(commits []PRCommit, mergeCommit *CommitRef, signatures map[string]Signature) {
+	for i := range commits {
+		if signature, ok := signatures[commits[i].Oid]; ok {
+			commits[i].Signature = &signature
+		}
+	}
+	if mergeCommit != nil {
+		if signature, ok := signatures[mergeCommit.Oid]; ok {
+			mergeCommit.Signature = &signature
+		}
+	}
+}
+
+// containsOid reports whether a commit list includes a commit
+func containsOid(commits []PRCommit, oid string) bool {
+	for _, commit := range commits {
+		if commit.Oid == oid {
+			return true
+		}
+	}
+	return false
+}
+
+// requiresSignedCommits reports whether a PR's base branch is one on which commits must be signed
+func requiresSignedCommits(pr PullRequest) bool {
+	if len(signedBranches) == 0 {
+		return true
+	}
+	for _, pattern := range signedBranches {
+		if matched, _ := path.Match(pattern, pr.BaseRefName); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// unsignedCommits describes the checked commits of a PR, including its merge commit, whose
+// signatures are missing or could not be verified
+func unsignedCommits(pr PullRequest) []string {
+	var unsigned []string
+	for _, commit := range pr.Commits {
+		if commit.Signature != nil && !commit.Signature.Verified {
+			unsigned = append(unsigned, fmt.Sprintf("%s (%s)", shortSHA(commit.Oid), commit.Signature.Reason))
+		}
+	}
+	if pr.MergeCommit != nil && pr.MergeCommit.Signature != nil && !pr.MergeCommit.Signature.Verified &&
+		!containsOid(pr.Commits, pr.MergeCommit.Oid) {
+		unsigned = append(unsigned, fmt.Sprintf("merge commit %s (%s)", shortSHA(pr.MergeCommit.Oid), pr.MergeCommit.Signature.Reason))
+	}
+	return unsigned
+}
+
+// signaturesChecked reports whether the signature of any of a PR's commits was verified
+func signaturesChecked(pr PullRequest) bool {
+	if pr.MergeCommit != nil && pr.MergeCommit.Signature != nil {
+		return true
+	}
+	for _, commit := range pr.Commits {
+		if commit.Signature != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// signatureStatus renders a commit signature for evidence packages
+func signatureStatus(signature *Signature) string {
+	if signature == nil {
+		return ""
+	}
+	status := "✅ verified"
+	if !signature.Verified {
+		status = "❌ " + strings.ReplaceAll(signature.Reason, "_", " ")
+	}
+	if signature.Signer != "" {
+		status += " (" + signature.Signer + ")"
+	}
+	return status
+}
@@ -0,0 +1,87 @@
+package main
+
+import "fmt"
+
+// defaultOversizedLines is the number of lines added plus deleted from which a PR is oversized
+const defaultOversizedLines = 1000
+
+var (
+	// oversizedLines and oversizedFiles are the configured oversized PR thresholds; 0 disables one
+	oversizedLines = defaultOversizedLines
+	oversizedFiles int
+)
+
+// Risk flags shown in the Risk column
+const (
+	riskOversized = "Oversized"
+	riskSensitive = "Sensitive Paths"
+	riskUnchecked = "Sensitive Paths Unverified"
+)
+
+// fillChangeVolume derives the change totals of PRs that only list their changed files,
+// such as exports made without the additions, deletions and changedFiles fields
+func fillChangeVolume(records []PRRecord) {
+	for i := range records {
+		pr := &records[i].PR
+		if pr.ChangedFiles > 0 || len(pr.Files) == 0 {
+			continue
+		}
+		pr.ChangedFiles = len(pr.Files)
+		for _, file := range pr.Files {
+			pr.Additions += file.Additions
+			pr.Deletions += file.Deletions
+		}
+	}
+}
+
+// changeVolumeKnown reports whether the provider reported the size of a PR
+func changeVolumeKnown(pr PullRequest) bool {
+	return pr.ChangedFiles > 0 || pr.Additions > 0 || pr.Deletions > 0
+}
+
+// changedLinesKnown reports whether the provider reported the lines a PR added and deleted;
+// Azure DevOps only counts files
+func changedLinesKnown(pr PullRequest) bool {
+	return changeVolumeKnown(pr) && !pr.LinesUnknown
+}
+
+// isOversized reports whether a PR is too large to review reliably
+func isOversized(pr PullRequest) bool {
+	return (oversizedLines > 0 && changedLinesKnown(pr) && pr.Additions+pr.Deletions >= oversizedLines) ||
+		(oversizedFiles > 0 && pr.ChangedFiles >= oversizedFiles)
+}
+
+// prRisks returns the risk flags of a pull request
+func prRisks(pr PullRequest) []string {
+	var risks []string
+	if isOversized(pr) {
+		risks = append(risks, riskOversized)
+	}
+	if isHighRisk(pr) {
+		risks = append(risks, riskSensitive)
+	}
+	if pr.SensitiveUnverified {
+		risks = append(risks, riskUnchecked)
+	}
+	return risks
+}
+
+// changeVolume renders the size of a PR for the markdown report
+func changeVolume(pr PullRequest) string {
+	volume := plural(pr.ChangedFiles, "file")
+	if changedLinesKnown(pr) {
+		volume = fmt.Sprintf("+%d/-%d in %s", pr.Additions, pr.Deletions, volume)
+	}
+	if len(pr.Commits) > 0 {
+		volume += ", " + plural(len(pr.Commits), "commit")
+	}
+	return volume
+}
+
+// plural formats a count with a singular or plural noun
+func plural(n int, noun string) string {
+	if n == 1 {
+		return fmt.Sprintf("%d %s", n, noun)
+	}
+	return fmt.Sprintf("%d %ss", n, noun)
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"strings"
+	"time"
+)
+
+// lastApproval returns the latest approval from a human other than the author submitted
+// before the merge
+func lastApproval(author string, reviews []Review, mergedAt *time.Time) *Review {
+	var last *Review
+	for i := range reviews {
+		review := &reviews[i]
+		if !preMergeHumanApproval(author, *review, mergedAt) {
+			continue
+		}
+		if last == nil || review.SubmittedAt.After(last.SubmittedAt) {
+			last = review
+		}
+	}
+	return last
+}
+
+// preMergeHumanApproval reports whether a review is an approval from a human other than the
+// author submitted before the merge
+func preMergeHumanApproval(author string, review Review, mergedAt *time.Time) bool {
+	if review.State != "APPROVED" || review.Author.Login == "" || review.Author.Login == author || isBotLogin(review.Author.Login) {
+		return false
+	}
+	return !review.SubmittedAt.IsZero() && (mergedAt == nil || !review.SubmittedAt.After(*mergedAt))
+}
+
+// approvalOfNewestCommit returns the pre-merge human approval given on the newest of the PR's
+// commits that was approved, so an approval of the final commit passes however early it came
+// relative to other approvals. When no approved commit is part of the PR, as after a force
+// push, it is the last approval.
+func approvalOfNewestCommit(author string, reviews []Review, mergedAt *time.Time, commits []PRCommit) *Review {
+	positions := make(map[string]int)
+	for i, commit := range commits {
+		positions[strings.ToLower(commit.Oid)] = i
+	}
+
+	var newest *Review
+	newestPosition := -1
+	for i := range reviews {
+		review := &reviews[i]
+		if review.Commit.Oid == "" || !preMergeHumanApproval(author, *review, mergedAt) {
+			continue
+		}
+		position, ok := positions[strings.ToLower(review.Commit.Oid)]
+		if !ok {
+			continue
+		}
+		if position > newestPosition || (position == newestPosition && review.SubmittedAt.After(newest.SubmittedAt)) {
+			newest, newestPosition = review, position
+		}
+	}
+	if newest != nil {
+		return newest
+	}
+	return lastApproval(author, reviews, mergedAt)
+}
+
+// commitsAfterApproval returns the commits that were added after an approval. Commits follow the
+// approved commit when it is part of the PR; otherwise, as after a force push, they are the
+// commits made after the approval was submitted.
+func commitsAfterApproval(approval *Review, commits []PRCommit) []PRCommit {
+	if approval == nil || len(commits) == 0 {
+		return nil
+	}
+	if approval.Commit.Oid != "" {
+		for i, commit := range commits {
+			if strings.EqualFold(commit.Oid, approval.Commit.Oid) {
+				return commits[i+1:]
+			}
+		}
+	}
+
+	var after []PRCommit
+	for _, commit := range commits {
+		if commit.CommittedDate.After(approval.SubmittedAt) {
+			after = append(after, commit)
+		}
+	}
+	return after
+}
+
+// shortSHAs returns the abbreviated object IDs of commits
+func shortSHAs(commits []PRCommit) []string {
+	var shas []string
+	for _, commit := range commits {
+		shas = append(shas, shortSHA(commit.Oid))
+	}
+	return shas
+}
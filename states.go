@@ -0,0 +1,195 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Pull request states, as reported by GitHub
+const (
+	stateMerged = "MERGED"
+	stateClosed = "CLOSED"
+	stateOpen   = "OPEN"
+)
+
+// prStates holds the --states values
+var prStates []string
+
+// stateSections describes the report section and worksheet of each state besides merged
+var stateSections = []struct {
+	State string
+	Icon  string
+	Name  string
+	Sheet string
+}{
+	{stateClosed, "🚫", "Closed Without Merging", "Closed PRs"},
+	{stateOpen, "🚧", "Open at Period End", "Open PRs"},
+}
+
+// parseStates turns --states values (merged, closed, open, all) into the states to fetch
+func parseStates(values []string) ([]string, error) {
+	wanted := make(map[string]bool)
+	for _, value := range values {
+		switch strings.ToLower(strings.TrimSpace(value)) {
+		case "merged":
+			wanted[stateMerged] = true
+		case "closed":
+			wanted[stateClosed] = true
+		case "open":
+			wanted[stateOpen] = true
+		case "all":
+			wanted[stateMerged], wanted[stateClosed], wanted[stateOpen] = true, true, true
+		default:
+			return nil, fmt.Errorf("unsupported state %q (supported: merged, closed, open, all)", value)
+		}
+	}
+	if len(wanted) == 0 {
+		wanted[stateMerged] = true
+	}
+
+	var states []string
+	for _, state := range []string{stateMerged, stateClosed, stateOpen} {
+		if wanted[state] {
+			states = append(states, state)
+		}
+	}
+	return states, nil
+}
+
+// filterStates returns the states a filter asks for, defaulting to merged
+func filterStates(filter *PRFilter) []string {
+	if filter == nil || len(filter.States) == 0 {
+		return []string{stateMerged}
+	}
+	return filter.States
+}
+
+// wantsState reports whether a filter asks for pull requests in a state
+func wantsState(filter *PRFilter, state string) bool {
+	for _, wanted := range filterStates(filter) {
+		if wanted == state {
+			return true
+		}
+	}
+	return false
+}
+
+// prState returns the state of a pull request, treating any PR with a merge date as merged
+func prState(pr PullRequest) string {
+	if pr.MergedAt != nil {
+		return stateMerged
+	}
+	return strings.ToUpper(pr.State)
+}
+
+// queryStates returns the states providers must fetch for a filter. PRs open at the end of a past
+// period may have been merged or closed since, so open PRs with an end date need every state.
+func queryStates(filter *PRFilter) []string {
+	if filter != nil && filter.EndDate != nil && wantsState(filter, stateOpen) {
+		return []string{stateMerged, stateClosed, stateOpen}
+	}
+	return filterStates(filter)
+}
+
+// openAt reports whether a pull request was open at a point in time
+func openAt(pr PullRequest, at time.Time) bool {
+	if pr.CreatedAt.After(at) {
+		return false
+	}
+	if pr.MergedAt != nil && !pr.MergedAt.After(at) {
+		return false
+	}
+	return pr.ClosedAt == nil || pr.ClosedAt.After(at)
+}
+
+// filterByPeriod keeps the pull requests in the filter's states that belong to its period: merged
+// and closed PRs by the date they were merged or closed, open PRs when open at the period end.
+// A PR merged or closed after the period end is reported as it was then: open.
+func filterByPeriod(prs []PullRequest, filter *PRFilter) []PullRequest {
+	var filtered []PullRequest
+	for _, pr := range prs {
+		if filter != nil && filter.EndDate != nil && wantsState(filter, stateOpen) && openAt(pr, *filter.EndDate) {
+			pr.State, pr.MergedAt, pr.ClosedAt = stateOpen, nil, nil
+			filtered = append(filtered, pr)
+			continue
+		}
+
+		state := prState(pr)
+		if !wantsState(filter, state) {
+			continue
+		}
+		if filter != nil {
+			switch state {
+			case stateMerged, stateClosed:
+				at := pr.MergedAt
+				if state == stateClosed {
+					at = pr.ClosedAt
+				}
+				if at == nil && (filter.StartDate != nil || filter.EndDate != nil) {
+					continue
+				}
+				if filter.StartDate != nil && at.Before(*filter.StartDate) {
+					continue
+				}
+				if filter.EndDate != nil && at.After(*filter.EndDate) {
+					continue
+				}
+			case stateOpen:
+				// Open PRs without an end date are the ones open now; with one, openAt decided above
+				if filter.EndDate != nil {
+					continue
+				}
+			}
+		}
+		filtered = append(filtered, pr)
+	}
+	return filtered
+}
+
+// recordsInState returns the records in a state, newest first
+func recordsInState(records []PRRecord, state string) []PRRecord {
+	var matching []PRRecord
+	for _, item := range records {
+		if prState(item.PR) == state {
+			matching = append(matching, item)
+		}
+	}
+	sort.SliceStable(matching, func(i, j int) bool {
+		return matching[i].PR.CreatedAt.After(matching[j].PR.CreatedAt)
+	})
+	return matching
+}
+
+// generateStateSection lists the closed-unmerged or open PRs of the report by repository
+func generateStateSection(output *os.File, title string, records []PRRecord) {
+	repoRecords := make(map[string][]PRRecord)
+	for _, item := range records {
+		repoRecords[item.RepositoryKey()] = append(repoRecords[item.RepositoryKey()], item)
+	}
+
+	fmt.Fprintf(output, "## %s\n\n", title)
+	fmt.Fprintf(output, "- **Pull Requests:** %d\n\n", len(records))
+	for _, repo := range sortedKeys(repoRecords) {
+		fmt.Fprintf(output, "### %s\n\n", repo)
+		for _, item := range repoRecords[repo] {
+			pr := item.PR
+			fmt.Fprintf(output, "%s", markdownLink(fmt.Sprintf("#%d", pr.Number), item.URL()))
+			if pr.Author.Login != "" {
+				fmt.Fprintf(output, " by **%s**", personName(pr.Author.Login))
+			}
+			fmt.Fprintf(output, " - opened %s", pr.CreatedAt.Format("2006-01-02"))
+			if pr.ClosedAt != nil {
+				fmt.Fprintf(output, " - closed %s", pr.ClosedAt.Format("2006-01-02"))
+			}
+			if pr.Provenance != "" {
+				fmt.Fprintf(output, " - 📂 %s", pr.Provenance)
+			}
+			fmt.Fprintf(output, "\n")
+		}
+		fmt.Fprintf(output, "\n")
+	}
+	fmt.Fprintf(output, "---\n\n")
+}
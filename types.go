@@ -1,12 +1,22 @@
 package main
 
-import "time"
+import (
+	"encoding/json"
+	"time"
+)
 
-// Repository represents a GitHub repository
-// Owner can be either a GitHub username or organization name
+// Repository represents a repository on GitHub or another supported provider
+// Owner can be either a GitHub username or organization name (or a GitLab group path)
 type Repository struct {
-	Owner string `yaml:"owner"` // GitHub username or organization name
-	Name  string `yaml:"name"`  // Repository name
+	Owner    string `yaml:"owner"`              // GitHub username or organization name
+	Name     string `yaml:"name"`               // Repository name
+	Host     string `yaml:"host,omitempty"`     // GitHub host (default: github.com), e.g. a GitHub Enterprise Server hostname
+	Provider string `yaml:"provider,omitempty"` // Code hosting provider: github (default), gitlab, bitbucket, azuredevops or local
+	BaseURL  string `yaml:"base_url,omitempty"` // Provider base URL for non-GitHub providers, e.g. https://gitlab.com
+	Path     string `yaml:"path,omitempty"`     // Local clone path for the local provider
+	Branch   string `yaml:"branch,omitempty"`   // Branch audited by the local provider (default: the clone's HEAD)
+
+	SensitivePaths []string `yaml:"sensitive_paths,omitempty"` // Path globs whose changes are high-risk, e.g. "terraform/**"
 }
 
 // Vertical represents a business vertical with its repositories
@@ -18,35 +28,79 @@ type Vertical struct {
 // RepositoriesConfig represents the configuration file structure
 type RepositoriesConfig struct {
 	Organization string       `yaml:"organization,omitempty"` // Optional: for single-org configs
+	Host         string       `yaml:"host,omitempty"`         // Optional: default host for repositories without one
+	Provider     string       `yaml:"provider,omitempty"`     // Optional: default provider for repositories without one
+	BaseURL      string       `yaml:"base_url,omitempty"`     // Optional: default provider base URL
 	Repositories []Repository `yaml:"repositories"`
 	Verticals    []Vertical   `yaml:"verticals,omitempty"` // Optional: for vertical-based configs
+
+	Policy `yaml:",inline"` // Optional: audit policy settings (bots, change types, ...)
+}
+
+// Policy holds the audit policy settings shared by every configuration format
+type Policy struct {
+	Bots        []string     `yaml:"bots,omitempty"`         // Login patterns of bot accounts, e.g. "renovate*"
+	ChangeTypes []ChangeType `yaml:"change_types,omitempty"` // Change categories matched by label or title prefix
+
+	SensitivePaths     []string `yaml:"sensitive_paths,omitempty"`     // Path globs that are high-risk in every repository
+	SensitiveApprovals int      `yaml:"sensitive_approvals,omitempty"` // Human approvals required on high-risk changes (default: 2)
+	OversizedLines     int      `yaml:"oversized_lines,omitempty"`     // Lines added plus deleted from which a PR is oversized (default: 1000)
+	OversizedFiles     int      `yaml:"oversized_files,omitempty"`     // Changed files from which a PR is oversized (default: no limit)
+	SignedBranches     []string `yaml:"signed_branches,omitempty"`     // Base branch globs on which commits must be signed (default: every branch)
+
+	ProductionEnvironment string `yaml:"production_environment,omitempty"` // Deployment environment that is production (default: production)
+}
+
+// ChangeType maps PR labels and title prefixes to a change category such as emergency or hotfix
+type ChangeType struct {
+	Name                string   `yaml:"name"`
+	Labels              []string `yaml:"labels,omitempty"`
+	TitlePrefixes       []string `yaml:"title_prefixes,omitempty"`
+	RetrospectiveWindow string   `yaml:"retrospective_window,omitempty"` // e.g. "72h": may merge without approval if reviewed within this window after merging
+	TicketPattern       string   `yaml:"ticket_pattern,omitempty"`       // Regular expression for a retrospective ticket reference, e.g. "RETRO-[0-9]+"
 }
 
 // SingleOrgConfig represents a simplified configuration for a single organization
 type SingleOrgConfig struct {
 	Organization string   `yaml:"organization"`
+	Host         string   `yaml:"host,omitempty"`
+	Provider     string   `yaml:"provider,omitempty"`
+	BaseURL      string   `yaml:"base_url,omitempty"`
 	Repositories []string `yaml:"repositories"`
+
+	Policy `yaml:",inline"` // Optional: audit policy settings (bots, change types, ...)
 }
 
 // RepositoryWithVerticals represents a repository with its associated verticals
+// Any Repository field (e.g. host) can be set per repository; owner defaults to the organization
 type RepositoryWithVerticals struct {
-	Name      string   `yaml:"name"`
-	Verticals []string `yaml:"verticals"`
+	Repository `yaml:",inline"`
+	Verticals  []string `yaml:"verticals"`
 }
 
 // SingleOrgVerticalConfig represents a simplified configuration with verticals
 type SingleOrgVerticalConfig struct {
 	Organization string   `yaml:"organization"`
+	Host         string   `yaml:"host,omitempty"`
+	Provider     string   `yaml:"provider,omitempty"`
+	BaseURL      string   `yaml:"base_url,omitempty"`
 	Verticals    []struct {
 		Name         string   `yaml:"name"`
 		Repositories []string `yaml:"repositories"`
 	} `yaml:"verticals"`
+
+	Policy `yaml:",inline"` // Optional: audit policy settings (bots, change types, ...)
 }
 
 // SingleOrgMultiVerticalConfig represents a configuration where repositories can have multiple verticals
 type SingleOrgMultiVerticalConfig struct {
 	Organization string                    `yaml:"organization"`
+	Host         string                    `yaml:"host,omitempty"`
+	Provider     string                    `yaml:"provider,omitempty"`
+	BaseURL      string                    `yaml:"base_url,omitempty"`
 	Repositories []RepositoryWithVerticals `yaml:"repositories"`
+
+	Policy `yaml:",inline"` // Optional: audit policy settings (bots, change types, ...)
 }
 
 // PullRequest represents a GitHub pull request
@@ -55,17 +109,181 @@ type PullRequest struct {
 	Title  string `json:"title"`
 	State  string `json:"state"`
 	MergedAt *time.Time `json:"mergedAt,omitempty"`
+	ClosedAt *time.Time `json:"closedAt,omitempty"`
 	CreatedAt time.Time `json:"createdAt"`
 	Author struct {
 		Login string `json:"login"`
+		IsBot bool   `json:"is_bot,omitempty"`
 	} `json:"author"`
+	URL                  string          `json:"url,omitempty"`
+	BaseRefName          string          `json:"baseRefName,omitempty"` // Target branch the PR was merged into
+	Body                 string          `json:"body,omitempty"`
+	Labels               []Label         `json:"labels,omitempty"`
+	ChangeType           string          `json:"changeType,omitempty"` // Change category from the configured change types
+	Additions            int             `json:"additions,omitempty"`
+	Deletions            int             `json:"deletions,omitempty"`
+	ChangedFiles         int             `json:"changedFiles,omitempty"`
+	LinesUnknown         bool            `json:"linesUnknown,omitempty"` // Whether the provider reports no line counts
+	Commits              []PRCommit      `json:"commits,omitempty"`
+	Files                []ChangedFile   `json:"files,omitempty"`
+	FilesIncomplete      bool            `json:"filesIncomplete,omitempty"` // Whether the provider left files out of the list
+	MergeCommit          *CommitRef      `json:"mergeCommit,omitempty"`
+	CodeOwners           []CodeOwnerRule `json:"codeOwners,omitempty"`           // CODEOWNERS rules owning the changed files at the merge commit
+	CodeOwnersUnverified string          `json:"codeOwnersUnverified,omitempty"` // Why CODEOWNERS could not be fully checked
+	SensitivePaths       []string        `json:"sensitivePaths,omitempty"`       // Changed files matching the repository's sensitive path globs
+	SensitiveUnverified  bool            `json:"sensitiveUnverified,omitempty"`  // Whether missing changed files left sensitive paths unchecked
+	IsRevert             bool            `json:"isRevert,omitempty"`             // Whether the PR reverts earlier changes
+	Reverts              []int           `json:"reverts,omitempty"`              // Numbers of the PRs this PR reverts
+	RevertedBy           []int           `json:"revertedBy,omitempty"`           // Numbers of the PRs that revert this PR
+	ReleasedIn           string          `json:"releasedIn,omitempty"`           // First release tag including the merge commit
+	ReleasedAt           *time.Time      `json:"releasedAt,omitempty"`
+	DeployedAt           *time.Time      `json:"deployedAt,omitempty"` // First production deployment including the merge commit
+	MergedBy             *Actor          `json:"mergedBy,omitempty"`
+	Reviews              []Review        `json:"reviews,omitempty"`
+	ReviewsUnknown       bool            `json:"reviewsUnknown,omitempty"` // Whether the provider failed to list the reviews
+	Unverified           []string        `json:"unverified,omitempty"`     // Parts of the PR the provider failed to fetch, with the errors
+	Provenance           string          `json:"provenance,omitempty"`     // Where the record came from when not a provider API, e.g. "local"
+	Exceptions           []Exception     `json:"exceptions,omitempty"`     // Control failures found when evaluating the PR
+}
+
+// Exception describes an audit control failure found on a pull request
+type Exception struct {
+	Type   string `json:"type"`
+	Detail string `json:"detail"`
+}
+
+// Label is a pull request label
+type Label struct {
+	Name string `json:"name"`
+}
+
+// CommitRef references a git commit by object ID
+type CommitRef struct {
+	Oid       string     `json:"oid"`
+	Signature *Signature `json:"signature,omitempty"`
+}
+
+// Signature is the verification status of a commit signature
+type Signature struct {
+	Verified bool   `json:"verified"`
+	Reason   string `json:"reason,omitempty"` // Verification state, e.g. "valid", "unsigned" or "unknown_key"
+	Signer   string `json:"signer,omitempty"` // Login or identity of the signer
+}
+
+// CodeOwnerRule is the CODEOWNERS rule that owns some of the files changed by a pull request
+type CodeOwnerRule struct {
+	Pattern string   `json:"pattern"`
+	Owners  []string `json:"owners"`
+}
+
+// Actor represents a GitHub user referenced by a pull request
+type Actor struct {
+	Login string `json:"login"`
+}
+
+// Review represents a review submitted on a pull request
+type Review struct {
+	Author      Actor     `json:"author"`
+	State       string    `json:"state"`
+	Body        string    `json:"body"`
+	SubmittedAt time.Time `json:"submittedAt"`
+	Commit      struct {
+		Oid string `json:"oid"`
+	} `json:"commit"`
+}
+
+// PRCommit represents a commit included in a pull request
+type PRCommit struct {
+	Oid             string         `json:"oid"`
+	MessageHeadline string         `json:"messageHeadline"`
+	MessageBody     string         `json:"messageBody,omitempty"`
+	Signature       *Signature     `json:"signature,omitempty"` // Signature verification, when checked
+	AuthoredDate    time.Time      `json:"authoredDate"`
+	CommittedDate   time.Time      `json:"committedDate"`
+	Authors         []CommitAuthor `json:"authors"`
+}
+
+// CommitAuthor represents an author of a pull request commit
+type CommitAuthor struct {
+	Login string `json:"login"`
+	Name  string `json:"name"`
+	Email string `json:"email"`
+}
+
+// ChangedFile represents a file changed by a pull request
+type ChangedFile struct {
+	Path      string `json:"path"`
+	Additions int    `json:"additions"`
+	Deletions int    `json:"deletions"`
+}
+
+// StatusCheck represents a check run or commit status reported on a pull request
+type StatusCheck struct {
+	Name        string `json:"name"`        // Check run name
+	Context     string `json:"context"`     // Commit status context
+	Status      string `json:"status"`      // Check run status
+	Conclusion  string `json:"conclusion"`  // Check run conclusion
+	State       string `json:"state"`       // Commit status state
+	DetailsURL  string `json:"detailsUrl"`  // Check run details link
+	TargetURL   string `json:"targetUrl"`   // Commit status link
+	CompletedAt string `json:"completedAt"` // Check run completion time
+}
+
+// PullRequestDetail represents the full record of a single pull request as returned by `gh pr view`
+type PullRequestDetail struct {
+	Number            int           `json:"number"`
+	Title             string        `json:"title"`
+	Body              string        `json:"body"`
+	State             string        `json:"state"`
+	URL               string        `json:"url"`
+	BaseRefName       string        `json:"baseRefName"`
+	HeadRefName       string        `json:"headRefName"`
+	Author            Actor         `json:"author"`
+	MergedBy          *Actor        `json:"mergedBy"`
+	CreatedAt         time.Time     `json:"createdAt"`
+	MergedAt          *time.Time    `json:"mergedAt"`
+	ClosedAt          *time.Time    `json:"closedAt"`
+	ReviewDecision    string        `json:"reviewDecision"`
+	Reviews           []Review      `json:"reviews"`
+	Commits           []PRCommit    `json:"commits"`
+	MergeCommit       *CommitRef    `json:"mergeCommit"`
+	Files             []ChangedFile `json:"files"`
+	StatusCheckRollup []StatusCheck `json:"statusCheckRollup"`
+	Comments          []struct {
+		Author    Actor     `json:"author"`
+		Body      string    `json:"body"`
+		CreatedAt time.Time `json:"createdAt"`
+	} `json:"comments"`
+}
+
+// PullRequestEvidence bundles everything fetched for a pull request evidence package
+type PullRequestEvidence struct {
+	Repository     string
+	Host           string
+	Detail         PullRequestDetail
+	Raw            json.RawMessage // Full `gh pr view` output
+	ReviewComments json.RawMessage // Inline review comments from the REST API
+	Timeline       json.RawMessage // Issue timeline events from the REST API
+}
+
+// PRRecord represents a pull request together with the repository and verticals it belongs to
+type PRRecord struct {
+	Repository string      `json:"repository"`
+	Host       string      `json:"host,omitempty"`
+	Provider   string      `json:"provider,omitempty"`
+	Verticals  []string    `json:"verticals,omitempty"`
+	PR         PullRequest `json:"pr"`
 }
 
 // PRFilter represents filtering options for pull requests
 type PRFilter struct {
 	StartDate *time.Time
 	EndDate   *time.Time
-	Limit     int // Maximum number of PRs to fetch per repository (0 = no limit)
+	Limit     int      // Maximum number of PRs to fetch per repository (0 = no limit)
+	States    []string // PR states to fetch: MERGED, CLOSED and/or OPEN (default: MERGED)
+	Authors   []string // Lower-case logins whose authored PRs are in scope
+	Reviewers []string // Lower-case logins whose approved PRs are in scope
+	MergedBy  []string // Lower-case logins whose merged PRs are in scope
 }
 
 // WorkerConfig represents configuration for concurrent processing
@@ -78,6 +296,8 @@ type WorkerConfig struct {
 // RepositoryResult represents the result of processing a single repository
 type RepositoryResult struct {
 	Repository string
+	Host       string
+	Provider   string
 	PRs        []PullRequest
 	Error      error
 }
@@ -0,0 +1,158 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// unassignedVertical groups repositories that are not part of any vertical
+const unassignedVertical = "Unassigned"
+
+// verticalRollup summarizes the merged PRs of a single vertical
+type verticalRollup struct {
+	Name         string
+	Repositories int
+	PRs          int
+	SharedPRs    int // PRs from repositories that also belong to another vertical
+	Exceptions   int
+}
+
+var nonSlugCharacters = regexp.MustCompile(`[^a-z0-9]+`)
+
+// recordVerticals returns the verticals a record belongs to, or the unassigned vertical
+func recordVerticals(item PRRecord) []string {
+	if len(item.Verticals) == 0 {
+		return []string{unassignedVertical}
+	}
+	return item.Verticals
+}
+
+// hasVerticals reports whether any record belongs to a configured vertical
+func hasVerticals(records []PRRecord) bool {
+	for _, item := range records {
+		if len(item.Verticals) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// groupByVertical returns the records of each vertical; records in several verticals appear in each
+func groupByVertical(records []PRRecord) map[string][]PRRecord {
+	groups := make(map[string][]PRRecord)
+	for _, item := range records {
+		for _, vertical := range recordVerticals(item) {
+			groups[vertical] = append(groups[vertical], item)
+		}
+	}
+	return groups
+}
+
+// buildVerticalRollup computes per-vertical totals plus a distinct total that counts
+// PRs from repositories shared across verticals only once
+func buildVerticalRollup(merged []PRRecord) ([]verticalRollup, verticalRollup) {
+	var rollups []verticalRollup
+	groups := groupByVertical(merged)
+	for _, name := range sortedKeys(groups) {
+		rollup := verticalRollup{Name: name}
+		repos := make(map[string]bool)
+		for _, item := range groups[name] {
+			repos[item.RepositoryKey()] = true
+			rollup.PRs++
+			if len(item.Verticals) > 1 {
+				rollup.SharedPRs++
+			}
+			if len(item.PR.Exceptions) > 0 {
+				rollup.Exceptions++
+			}
+		}
+		rollup.Repositories = len(repos)
+		rollups = append(rollups, rollup)
+	}
+
+	total := verticalRollup{Name: "Total (distinct)"}
+	repos := make(map[string]bool)
+	for _, item := range merged {
+		repos[item.RepositoryKey()] = true
+		total.PRs++
+		if len(item.Verticals) > 1 {
+			total.SharedPRs++
+		}
+		if len(item.PR.Exceptions) > 0 {
+			total.Exceptions++
+		}
+	}
+	total.Repositories = len(repos)
+
+	return rollups, total
+}
+
+// generateVerticalRollup writes the per-vertical totals table
+func generateVerticalRollup(output *os.File, merged []PRRecord) {
+	rollups, total := buildVerticalRollup(merged)
+
+	fmt.Fprintf(output, "## Vertical Rollup\n\n")
+	fmt.Fprintf(output, "| Vertical | Repositories | Merged PRs | Shared with Other Verticals | PRs with Exceptions |\n")
+	fmt.Fprintf(output, "|---|---:|---:|---:|---:|\n")
+	for _, rollup := range rollups {
+		fmt.Fprintf(output, "| %s | %d | %d | %d | %d |\n", rollup.Name, rollup.Repositories, rollup.PRs, rollup.SharedPRs, rollup.Exceptions)
+	}
+	fmt.Fprintf(output, "| **%s** | **%d** | **%d** | **%d** | **%d** |\n\n", total.Name, total.Repositories, total.PRs, total.SharedPRs, total.Exceptions)
+	fmt.Fprintf(output, "_PRs from repositories shared by several verticals are listed under each of them but counted once in the distinct total._\n")
+	fmt.Fprintf(output, "\n---\n\n")
+}
+
+// generateVerticalSections writes one section per vertical containing its repository sections
+func generateVerticalSections(output *os.File, merged []PRRecord) {
+	groups := groupByVertical(merged)
+	for _, vertical := range sortedKeys(groups) {
+		repoPRs := make(map[string][]PRRecord)
+		for _, item := range groups[vertical] {
+			repoPRs[item.RepositoryKey()] = append(repoPRs[item.RepositoryKey()], item)
+		}
+
+		fmt.Fprintf(output, "## Vertical: %s\n\n", vertical)
+		fmt.Fprintf(output, "- **Repositories with Merged PRs:** %d\n", len(repoPRs))
+		fmt.Fprintf(output, "- **Merged Pull Requests:** %d\n\n", len(groups[vertical]))
+
+		for _, repo := range sortedKeys(repoPRs) {
+			generateRepositorySection(output, "###", repo, repoPRs[repo])
+		}
+	}
+}
+
+// outputPerVertical writes a separate markdown, XLSX, HTML and JSON report for each vertical
+func outputPerVertical(allPRs []PRRecord) {
+	groups := groupByVertical(allPRs)
+	base := strings.TrimSuffix(outputFile, ".md")
+	for _, vertical := range sortedKeys(groups) {
+		reportFile := fmt.Sprintf("%s-%s.md", base, verticalSlug(vertical))
+		outputResults(reportFile, groups[vertical])
+		outputXLSX(reportFile, groups[vertical])
+		outputHTML(reportFile, groups[vertical])
+		outputJSON(reportFile, groups[vertical])
+		fmt.Printf("🏢 %s report generated: %s\n", vertical, reportFile)
+	}
+}
+
+// verticalSlug turns a vertical name into a file name component
+func verticalSlug(name string) string {
+	slug := strings.Trim(nonSlugCharacters.ReplaceAllString(strings.ToLower(name), "-"), "-")
+	if slug == "" {
+		return "vertical"
+	}
+	return slug
+}
+
+// sortedKeys returns the keys of a map in sorted order
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,384 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/tealeg/xlsx/v3"
+)
+
+// maxSheetNameLength is Excel's worksheet name limit
+const maxSheetNameLength = 31
+
+// xlsxDateOptions renders date cells as real Excel dates in ISO format
+var xlsxDateOptions = xlsx.DateTimeOptions{
+	Location:        time.UTC,
+	ExcelTimeFormat: "yyyy-mm-dd",
+}
+
+func outputXLSX(reportFile string, allPRs []PRRecord) {
+	// Create XLSX filename based on output file
+	xlsxFile := strings.TrimSuffix(reportFile, ".md") + ".xlsx"
+
+	// Create a new Excel file
+	file := xlsx.NewFile()
+
+	// Group PRs by repository with vertical info - only include merged PRs
+	var merged []PRRecord
+	repoPRs := make(map[string][]PRRecord)
+	for _, item := range allPRs {
+		// Only include PRs that are actually merged (have a merge date)
+		if item.PR.MergedAt != nil && item.PR.State == "MERGED" {
+			merged = append(merged, item)
+			repoPRs[item.RepositoryKey()] = append(repoPRs[item.RepositoryKey()], item)
+		}
+	}
+	sortRecordsByMergeDate(merged)
+
+	var repos []string
+	for repo := range repoPRs {
+		repos = append(repos, repo)
+		sortRecordsByMergeDate(repoPRs[repo])
+	}
+	sort.Strings(repos)
+
+	usedNames := make(map[string]bool)
+
+	// The summary sheet is added first so it opens as the front sheet, and filled in
+	// once the repository sheet names are known
+	summarySheet, err := file.AddSheet(uniqueSheetName("Summary", usedNames))
+	if err != nil {
+		log.Printf("Failed to create Excel summary sheet: %v", err)
+		return
+	}
+
+	allSheet, err := file.AddSheet(uniqueSheetName("All PRs", usedNames))
+	if err != nil {
+		log.Printf("Failed to create Excel consolidated sheet: %v", err)
+		return
+	}
+	writePRSheet(allSheet, merged)
+
+	metricsSheet, err := file.AddSheet(uniqueSheetName("Metrics", usedNames))
+	if err != nil {
+		log.Printf("Failed to create Excel metrics sheet: %v", err)
+		return
+	}
+	writeMetricsSheet(metricsSheet, merged)
+
+	// Scoped audits get a row per person, role and PR
+	if hasPeopleScope(peopleFilter) {
+		sheet, err := file.AddSheet(uniqueSheetName("People", usedNames))
+		if err != nil {
+			log.Printf("Failed to create Excel people sheet: %v", err)
+		} else {
+			writePeopleSheet(sheet, merged, peopleFilter)
+		}
+	}
+
+	// Merged PRs that never reached production
+	if never := neverDeployedRecords(merged); len(never) > 0 {
+		sheet, err := file.AddSheet(uniqueSheetName("Never Deployed", usedNames))
+		if err != nil {
+			log.Printf("Failed to create Excel sheet for never deployed PRs: %v", err)
+		} else {
+			writePRSheet(sheet, never)
+		}
+	}
+
+	// Closed-unmerged and open PRs, when requested, get a worksheet per state
+	for _, section := range stateSections {
+		records := recordsInState(allPRs, section.State)
+		if len(records) == 0 {
+			continue
+		}
+		sheet, err := file.AddSheet(uniqueSheetName(section.Sheet, usedNames))
+		if err != nil {
+			log.Printf("Failed to create Excel sheet for %s PRs: %v", strings.ToLower(section.State), err)
+			continue
+		}
+		writePRSheet(sheet, records)
+	}
+
+	// Create a worksheet for each repository
+	repoSheets := make(map[string]string)
+	for _, repo := range repos {
+		sheetName := uniqueSheetName(repositorySheetName(repo, repoPRs[repo][0].Verticals), usedNames)
+		sheet, err := file.AddSheet(sheetName)
+		if err != nil {
+			log.Printf("Failed to create Excel sheet for %s: %v", repo, err)
+			continue
+		}
+		writePRSheet(sheet, repoPRs[repo])
+		repoSheets[repo] = sheetName
+	}
+
+	writeSummarySheet(summarySheet, allPRs, merged, repos, repoSheets)
+
+	// Save the file
+	if err := file.Save(xlsxFile); err != nil {
+		log.Printf("Failed to save Excel file: %v", err)
+		return
+	}
+
+	recordArtifact(xlsxFile)
+	fmt.Printf("📊 Excel report generated: %s\n", xlsxFile)
+}
+
+// writeSummarySheet writes PR counts per vertical and per repository, linking each repository to its sheet
+func writeSummarySheet(sheet *xlsx.Sheet, allPRs, merged []PRRecord, repos []string, repoSheets map[string]string) {
+	bold := xlsxHeaderStyle()
+
+	title := sheet.AddRow().AddCell()
+	title.SetString("Merged Pull Request Analysis Report")
+	title.SetStyle(bold)
+
+	row := sheet.AddRow()
+	row.AddCell().SetString("Generated")
+	row.AddCell().SetDateWithOptions(time.Now(), xlsx.DateTimeOptions{Location: time.Local, ExcelTimeFormat: "yyyy-mm-dd hh:mm:ss"})
+	row = sheet.AddRow()
+	row.AddCell().SetString("Total Repositories with Merged PRs")
+	row.AddCell().SetInt(len(repos))
+	row = sheet.AddRow()
+	row.AddCell().SetString("Total Merged Pull Requests")
+	row.AddCell().SetInt(len(merged))
+	_, automated := splitBotRecords(merged)
+	row = sheet.AddRow()
+	row.AddCell().SetString("Automated (Bot-Authored) Pull Requests")
+	row.AddCell().SetInt(len(automated))
+	row = sheet.AddRow()
+	row.AddCell().SetString("High-Risk (Sensitive Path) Pull Requests")
+	row.AddCell().SetInt(len(highRiskRecords(merged)))
+	if unverified := sensitiveUnverifiedRecords(merged); len(unverified) > 0 {
+		row = sheet.AddRow()
+		row.AddCell().SetString("Pull Requests With Unverified Sensitive Paths")
+		row.AddCell().SetInt(len(unverified))
+	}
+	oversized := 0
+	for _, item := range merged {
+		if isOversized(item.PR) {
+			oversized++
+		}
+	}
+	row = sheet.AddRow()
+	row.AddCell().SetString("Oversized Pull Requests")
+	row.AddCell().SetInt(oversized)
+	reverted := 0
+	for _, item := range merged {
+		if isReverted(item.PR) {
+			reverted++
+		}
+	}
+	if len(deploymentRepos) > 0 {
+		row = sheet.AddRow()
+		row.AddCell().SetString("Merged but Never Deployed Pull Requests")
+		row.AddCell().SetInt(len(neverDeployedRecords(merged)))
+	}
+	row = sheet.AddRow()
+	row.AddCell().SetString("Revert Pull Requests")
+	row.AddCell().SetInt(len(revertRecords(merged)))
+	row = sheet.AddRow()
+	row.AddCell().SetString("Reverted Pull Requests")
+	row.AddCell().SetInt(reverted)
+	for _, section := range stateSections {
+		if count := len(recordsInState(allPRs, section.State)); count > 0 {
+			row = sheet.AddRow()
+			row.AddCell().SetString(section.Name)
+			row.AddCell().SetInt(count)
+		}
+	}
+	sheet.AddRow()
+
+	// Per-vertical counts, with shared repositories counted once in the total
+	repoCounts := make(map[string]int)
+	repoVerticals := make(map[string][]string)
+	for _, item := range merged {
+		repoCounts[item.RepositoryKey()]++
+		repoVerticals[item.RepositoryKey()] = item.Verticals
+	}
+
+	rollups, total := buildVerticalRollup(merged)
+	addHeaderRow(sheet, bold, "Vertical", "Repositories", "Merged PRs", "Shared with Other Verticals", "PRs with Exceptions")
+	for _, rollup := range append(rollups, total) {
+		row := sheet.AddRow()
+		row.AddCell().SetString(rollup.Name)
+		row.AddCell().SetInt(rollup.Repositories)
+		row.AddCell().SetInt(rollup.PRs)
+		row.AddCell().SetInt(rollup.SharedPRs)
+		row.AddCell().SetInt(rollup.Exceptions)
+	}
+	sheet.AddRow()
+
+	// Per-repository counts with links to the repository sheets
+	addHeaderRow(sheet, bold, "Repository", "Verticals", "Merged PRs", "Sheet")
+	for _, repo := range repos {
+		row := sheet.AddRow()
+		row.AddCell().SetString(repo)
+		row.AddCell().SetString(strings.Join(repoVerticals[repo], ", "))
+		row.AddCell().SetInt(repoCounts[repo])
+		if sheetName, ok := repoSheets[repo]; ok {
+			row.AddCell().SetHyperlink(fmt.Sprintf("'%s'!A1", strings.ReplaceAll(sheetName, "'", "''")), sheetName, "")
+		}
+	}
+
+	sheet.SetColWidth(1, 1, 40)
+	sheet.SetColWidth(2, 5, 22)
+}
+
+// writePRSheet writes a pull request table with a bold, frozen, filterable header row
+func writePRSheet(sheet *xlsx.Sheet, records []PRRecord) {
+	bold := xlsxHeaderStyle()
+
+	var headers []string
+	for _, column := range prColumns {
+		headers = append(headers, column.Header)
+	}
+	addHeaderRow(sheet, bold, headers...)
+
+	for _, item := range records {
+		addPRCells(sheet.AddRow(), item)
+	}
+
+	for i, column := range prColumns {
+		sheet.SetColWidth(i+1, i+1, column.Width)
+	}
+	freezeHeaderRow(sheet)
+	sheet.AutoFilter = &xlsx.AutoFilter{
+		TopLeftCell:     "A1",
+		BottomRightCell: xlsx.GetCellIDStringFromCoords(len(prColumns)-1, len(records)),
+	}
+}
+
+// setLinkCell links a cell to a web URL, or writes plain text when there is none. The
+// library treats links without an http(s) scheme as locations within the workbook.
+func setLinkCell(cell *xlsx.Cell, url, text string) {
+	if strings.HasPrefix(url, "http://") || strings.HasPrefix(url, "https://") {
+		cell.SetHyperlink(url, text, "")
+		return
+	}
+	cell.SetString(text)
+}
+
+// addPRCells appends the prColumns cells of a pull request to a row
+func addPRCells(row *xlsx.Row, item PRRecord) {
+	for _, column := range prColumns {
+		cell := row.AddCell()
+		value := column.Value(item)
+
+		switch column.Kind {
+		case columnLink:
+			setLinkCell(cell, item.URL(), value)
+		case columnDate:
+			if t := column.Date(item); t != nil {
+				cell.SetDateWithOptions(*t, xlsxDateOptions)
+			}
+		case columnNumber:
+			if n, err := strconv.Atoi(value); err == nil {
+				cell.SetInt(n)
+			} else {
+				cell.SetString(value)
+			}
+		default:
+			cell.SetString(value)
+		}
+	}
+}
+
+// addHeaderRow adds a row of bold header cells
+func addHeaderRow(sheet *xlsx.Sheet, style *xlsx.Style, headers ...string) {
+	row := sheet.AddRow()
+	for _, header := range headers {
+		cell := row.AddCell()
+		cell.SetString(header)
+		cell.SetStyle(style)
+	}
+}
+
+// freezeHeaderRow keeps the first row visible while scrolling
+func freezeHeaderRow(sheet *xlsx.Sheet) {
+	sheet.SheetViews = []xlsx.SheetView{{
+		Pane: &xlsx.Pane{
+			YSplit:      1,
+			TopLeftCell: "A2",
+			ActivePane:  "bottomLeft",
+			State:       "frozen",
+		},
+	}}
+}
+
+// xlsxHeaderStyle returns the bold, shaded style used for header cells
+func xlsxHeaderStyle() *xlsx.Style {
+	style := xlsx.NewStyle()
+	style.Font.Bold = true
+	style.Fill = *xlsx.NewFill("solid", "FFD9E1F2", "FFD9E1F2")
+	style.ApplyFont = true
+	style.ApplyFill = true
+	return style
+}
+
+// repositorySheetName builds a worksheet name from the repository name and its verticals
+func repositorySheetName(repoName string, verticals []string) string {
+	// Extract just the repository name (remove organization prefix)
+	repoNameOnly := repoName
+	if strings.Contains(repoName, "/") {
+		parts := strings.Split(repoName, "/")
+		repoNameOnly = parts[len(parts)-1] // Get the last part (repository name)
+	}
+
+	if len(verticals) == 0 {
+		return repoNameOnly
+	}
+	// Join multiple verticals with "-" since "/" is not allowed in sheet names
+	return fmt.Sprintf("%s - %s", strings.Join(verticals, "-"), repoNameOnly)
+}
+
+// uniqueSheetName returns a valid Excel worksheet name that is not yet in use,
+// replacing forbidden characters, truncating to 31 characters and appending an
+// index such as " (2)" when a truncated name collides with an earlier sheet
+func uniqueSheetName(name string, used map[string]bool) string {
+	name = strings.Map(func(r rune) rune {
+		switch r {
+		case ':', '\\', '/', '?', '*', '[', ']':
+			return '-'
+		}
+		return r
+	}, name)
+	name = strings.Trim(name, "'")
+	if name == "" {
+		name = "Sheet"
+	}
+
+	candidate := truncateRunes(name, maxSheetNameLength)
+	for i := 2; used[strings.ToLower(candidate)]; i++ {
+		suffix := fmt.Sprintf(" (%d)", i)
+		candidate = truncateRunes(name, maxSheetNameLength-utf8.RuneCountInString(suffix)) + suffix
+	}
+
+	// Excel compares sheet names case-insensitively
+	used[strings.ToLower(candidate)] = true
+	return candidate
+}
+
+// truncateRunes shortens s to at most n runes
+func truncateRunes(s string, n int) string {
+	if utf8.RuneCountInString(s) <= n {
+		return s
+	}
+	return string([]rune(s)[:n])
+}
+
+// sortRecordsByMergeDate sorts records newest merge first, then by PR number
+func sortRecordsByMergeDate(records []PRRecord) {
+	sort.SliceStable(records, func(i, j int) bool {
+		a, b := records[i].PR.MergedAt, records[j].PR.MergedAt
+		if a != nil && b != nil && !a.Equal(*b) {
+			return a.After(*b)
+		}
+		return records[i].PR.Number > records[j].PR.Number
+	})
+}